@@ -1,8 +1,7 @@
 package config
 
 import (
-	"os"
-	"strings"
+	"fmt"
 
 	"github.com/skos-ninja/config-loader/pkg/context"
 	"github.com/skos-ninja/config-loader/pkg/parser"
@@ -10,51 +9,84 @@ import (
 	"github.com/spf13/cobra"
 )
 
-var configFlag = ""
+var configFlag = []string{}
+var envFileFlag = []string{}
 
-func Init(cmd *cobra.Command) {
-	cmd.PersistentFlags().StringVar(&configFlag, "config", configFlag, "Set the json config data (Input types: file path, environment var name, flag name)")
+type ErrUnknownSource struct {
+	source string
 }
 
-func Load(cmd *cobra.Command, config interface{}) error {
-	return load(cmd, config)
+func (e ErrUnknownSource) Error() string {
+	return fmt.Sprintf("Unknown config source: %s", e.source)
 }
 
-func MustLoad(cmd *cobra.Command, config interface{}) {
-	err := load(cmd, config)
+// Init registers the config and env-file flags on the command
+func Init(cmd *cobra.Command, opts ...InitOption) {
+	initOpts = newInitOptions(opts)
+	cmd.PersistentFlags().StringSliceVar(&configFlag, "config", []string{}, "Set the json, yaml or toml config data. Repeat or comma separate to merge multiple configs in order (Input types: file path, file:path, env:name, flag:name, - for stdin). When not set a config file is searched for")
+	cmd.PersistentFlags().StringSliceVar(&envFileFlag, "env-file", []string{}, "Set dotenv files to read env variables from. Repeat or comma separate to read multiple files with later files taking precedence")
+}
+
+// RegisterFlags creates a typed flag on the command for each field of the config with a flag tag using the
+// field's current value as the default. Options such as WithAutoNames are applied so the names match Load.
+//
+// The usage, short, hidden and persistent tags configure each flag and registering a name twice returns an error.
+func RegisterFlags(cmd *cobra.Command, config interface{}, opts ...Option) error {
+	o := newOptions(opts)
+
+	ctx := context.GetContextWithCmd(cmd)
+	if o.autoNames {
+		ctx = parser.WithAutoNames(ctx, o.autoPrefix)
+	}
+
+	return parser.RegisterFlags(ctx, config)
+}
+
+// Load applies each source to the config in order of precedence
+func Load(cmd *cobra.Command, config interface{}, opts ...Option) error {
+	return load(cmd, config, newOptions(opts))
+}
+
+// MustLoad calls Load and panics on any error
+func MustLoad(cmd *cobra.Command, config interface{}, opts ...Option) {
+	err := load(cmd, config, newOptions(opts))
 	if err != nil {
 		panic(err)
 	}
 }
 
-func load(cmd *cobra.Command, config interface{}) error {
+func load(cmd *cobra.Command, config interface{}, o *options) error {
 	ctx := context.GetContextWithCmd(cmd)
 
-	// Try to read the config json from a file
-	s, _ := os.ReadFile(configFlag)
-	err := setJSONConfig(string(s), config)
+	ctx, err := withEnvFiles(ctx, envFileFlag, o)
 	if err != nil {
 		return err
 	}
-
-	// Try to read the config json from an env
-	d, _ := parser.EnvironmentParser{}.GetString(ctx, strings.ToUpper(configFlag))
-	err = setJSONConfig(d, config)
-	if err != nil {
-		return err
+	if o.autoNames {
+		ctx = parser.WithAutoNames(ctx, o.autoPrefix)
 	}
-
-	// Try to read the config json from a flag
-	flag, _ := parser.FlagParser{}.GetString(ctx, configFlag)
-	err = setJSONConfig(flag, config)
-	if err != nil {
-		return err
+	for _, d := range o.directories {
+		ctx = parser.WithFieldParser(ctx, d.source, parser.DirectoryParser{Dir: d.dir})
 	}
 
-	// Perform parsing on each field
-	err = parser.ParseStruct(ctx, config, false)
-	if err != nil {
-		return err
+	for _, source := range o.precedence {
+		if source == SourceConfig {
+			err := loadConfig(ctx, config, o)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if _, ok := parser.GetFieldParser(ctx, source); !ok {
+			return ErrUnknownSource{source}
+		}
+
+		// Perform parsing on each field for just this source
+		err := parser.ParseStruct(parser.WithPrecedence(ctx, []string{source}), config, false)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
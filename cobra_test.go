@@ -1,8 +1,13 @@
 package config
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
+	"github.com/skos-ninja/config-loader/pkg/parser"
 	"github.com/spf13/cobra"
 )
 
@@ -23,3 +28,245 @@ func TestLoad(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadPrecedence(t *testing.T) {
+	type Test struct {
+		Str string `json:"str" env:"TEST_LOAD_PRECEDENCE" flag:"test-load-precedence"`
+	}
+
+	path := filepath.Join(t.TempDir(), "config.json")
+	writeFile(t, path, `{"str": "config-value"}`)
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+	cmd.Flags().String("test-load-precedence", "", "")
+	if err := cmd.PersistentFlags().Set("config", path); err != nil {
+		t.Fatal(err)
+	}
+	if err := cmd.Flags().Set("test-load-precedence", "flag-value"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_LOAD_PRECEDENCE", "env-value")
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Default precedence",
+			want: "flag-value",
+		},
+		{
+			name: "Config over env and flag",
+			opts: []Option{WithPrecedence(SourceFlag, SourceEnv, SourceConfig)},
+			want: "config-value",
+		},
+		{
+			name: "Env over config and flag",
+			opts: []Option{WithPrecedence(SourceConfig, SourceFlag, SourceEnv)},
+			want: "env-value",
+		},
+		{
+			name: "No sources",
+			opts: []Option{WithPrecedence()},
+			want: "default",
+		},
+		{
+			name:    "Unknown source",
+			opts:    []Option{WithPrecedence("unknown")},
+			want:    "default",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				cfg := &Test{Str: "default"}
+				err := Load(cmd, cfg, tt.opts...)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if cfg.Str != tt.want {
+					t.Fatalf("Load() iteration %d = %v, want %v", i, cfg.Str, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestLoadMultipleConfigs(t *testing.T) {
+	type Test struct {
+		A      string   `json:"a"`
+		B      string   `json:"b"`
+		C      string   `json:"c"`
+		Append []string `json:"append" merge:"append"`
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.json"), `{"a": "base", "b": "base", "c": "base", "append": ["base"]}`)
+	writeFile(t, filepath.Join(dir, "prod.yaml"), "b: prod\nc: prod\nappend: [prod]\n")
+	writeFile(t, filepath.Join(dir, "local.toml"), "c = \"local\"\nappend = [\"local\"]\n")
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+	err := cmd.ParseFlags([]string{
+		"--config", filepath.Join(dir, "base.json"),
+		"--config", filepath.Join(dir, "prod.yaml") + "," + filepath.Join(dir, "local.toml"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Test{}
+	err = Load(cmd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{A: "base", B: "prod", C: "local", Append: []string{"base", "prod", "local"}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestLoadAutoNames(t *testing.T) {
+	type Database struct {
+		MaxConns int
+		Host     string
+	}
+	type Test struct {
+		Database Database
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+	cmd.Flags().String("database-host", "", "")
+	if err := cmd.Flags().Set("database-host", "flag-host"); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("TEST_LOAD_AUTO_DATABASE_MAX_CONNS", "10")
+	t.Setenv("TEST_LOAD_AUTO_DATABASE_HOST", "env-host")
+
+	cfg := &Test{}
+	err := Load(cmd, cfg, WithAutoNames("TEST_LOAD_AUTO"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Test{Database: Database{MaxConns: 10, Host: "flag-host"}}
+	if *cfg != want {
+		t.Errorf("Load() = %+v, want %+v", *cfg, want)
+	}
+
+	cfg = &Test{}
+	err = Load(cmd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *cfg != (Test{}) {
+		t.Errorf("Load() without auto names = %+v, want untagged fields unset", *cfg)
+	}
+}
+
+func TestRegisterFlags(t *testing.T) {
+	type Database struct {
+		MaxConns int `usage:"Maximum connections"`
+	}
+	type Test struct {
+		Name     string `flag:"name"`
+		Database Database
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+	cfg := &Test{Name: "default", Database: Database{MaxConns: 10}}
+	err := RegisterFlags(cmd, cfg, WithAutoNames("TEST_REGISTER_FLAGS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := cmd.Flags().Lookup("database-max-conns"); f == nil || f.DefValue != "10" || f.Usage != "Maximum connections" {
+		t.Fatalf("RegisterFlags() database-max-conns = %+v", f)
+	}
+
+	err = cmd.ParseFlags([]string{"--database-max-conns", "20"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = Load(cmd, cfg, WithAutoNames("TEST_REGISTER_FLAGS"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Test{Name: "default", Database: Database{MaxConns: 20}}
+	if *cfg != want {
+		t.Errorf("Load() = %+v, want %+v", *cfg, want)
+	}
+
+	err = RegisterFlags(cmd, cfg)
+	if !errors.As(err, &parser.ErrDuplicateFlag{}) {
+		t.Errorf("RegisterFlags() error = %v, want ErrDuplicateFlag", err)
+	}
+
+	// Subcommands inherit the persistent --config flag from the root command
+	sub := &cobra.Command{Use: "sub"}
+	cmd.AddCommand(sub)
+	err = RegisterFlags(sub, &struct {
+		Config string `flag:"config"`
+	}{})
+	if !errors.As(err, &parser.ErrDuplicateFlag{}) {
+		t.Errorf("RegisterFlags() subcommand error = %v, want ErrDuplicateFlag", err)
+	}
+}
+
+func writeFile(t *testing.T, path string, data string) {
+	err := os.WriteFile(path, []byte(data), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoadRegisteredParser(t *testing.T) {
+	type Test struct {
+		Secret string `vault:"secret"`
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "secret"), "from vault")
+	parser.FieldParsers["vault"] = parser.DirectoryParser{Dir: dir}
+	defer delete(parser.FieldParsers, "vault")
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+	cfg := &Test{}
+	err := Load(cmd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Secret != "from vault" {
+		t.Errorf("Load() = %+v, want %v", cfg, "from vault")
+	}
+}
+
+func TestLoadIntFlag(t *testing.T) {
+	type Test struct {
+		Port int `flag:"port"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+	cmd.Flags().Int("port", 80, "")
+	if err := cmd.ParseFlags([]string{"--port", "8080"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Test{}
+	err := Load(cmd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Port != 8080 {
+		t.Errorf("Load() = %+v, want %v", cfg, 8080)
+	}
+}
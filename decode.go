@@ -0,0 +1,140 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type ErrUnsupportedConfigValue struct {
+	key   string
+	value interface{}
+}
+
+func (e ErrUnsupportedConfigValue) Error() string {
+	return fmt.Sprintf("Unsupported config value for %s: %v", e.key, e.value)
+}
+
+// configDecoder decodes config data into generic maps, slices and values that can be encoded as json
+type configDecoder func(configStr string) (interface{}, error)
+
+// decoders is a collection of file extensions to the decoder used for them
+var decoders = map[string]configDecoder{
+	".json": decodeJSON,
+	".yaml": decodeYAML,
+	".yml":  decodeYAML,
+	".toml": decodeTOML,
+}
+
+var (
+	tomlTableRegex = regexp.MustCompile(`^\[\[?\s*[A-Za-z0-9_."' -]+\s*\]\]?\s*(#.*)?$`)
+	tomlKeyRegex   = regexp.MustCompile(`^[A-Za-z0-9_."'-]+(\s*\.\s*[A-Za-z0-9_."'-]+)*\s*=`)
+)
+
+// setConfig decodes the config data using the decoder for the name's file extension.
+// If the extension is not known the format is detected from the data.
+func setConfig(name string, configStr string, config interface{}) error {
+	v, err := decodeConfig(name, configStr)
+	if err != nil {
+		return err
+	}
+
+	return setDecodedConfig(v, config)
+}
+
+// decodeConfig decodes the config data using the decoder for the name's file extension
+func decodeConfig(name string, configStr string) (interface{}, error) {
+	if d, ok := decoders[strings.ToLower(filepath.Ext(name))]; ok {
+		return d(configStr)
+	}
+
+	return sniffDecoder(configStr)(configStr)
+}
+
+// setDecodedConfig sets the decoded config data onto the config
+func setDecodedConfig(v interface{}, config interface{}) error {
+	if v == nil {
+		return nil
+	}
+
+	if err := convertUnitFields(v, reflect.TypeOf(config)); err != nil {
+		return err
+	}
+	if err := checkConfigValues(v, ""); err != nil {
+		return err
+	}
+
+	// Round trip through json so all formats map fields the same way
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return setJSONConfig(string(b), config)
+}
+
+// checkConfigValues returns an error naming the key of any value that can't be encoded as json,
+// such as the .inf and .nan floats yaml and toml support
+func checkConfigValues(v interface{}, key string) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			name := k
+			if key != "" {
+				name = key + "." + k
+			}
+			if err := checkConfigValues(e, name); err != nil {
+				return err
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			if err := checkConfigValues(e, key+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case []map[string]interface{}:
+		for i, e := range v {
+			if err := checkConfigValues(e, key+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+	case float64:
+		if math.IsInf(v, 0) || math.IsNaN(v) {
+			return ErrUnsupportedConfigValue{key: key, value: v}
+		}
+	case float32:
+		if math.IsInf(float64(v), 0) || math.IsNaN(float64(v)) {
+			return ErrUnsupportedConfigValue{key: key, value: v}
+		}
+	}
+
+	return nil
+}
+
+// sniffDecoder returns the decoder to use for data of an unknown format
+func sniffDecoder(configStr string) configDecoder {
+	// Find the first line that isn't blank or a comment
+	line := ""
+	for _, l := range strings.Split(configStr, "\n") {
+		l = strings.TrimSpace(l)
+		if l != "" && !strings.HasPrefix(l, "#") {
+			line = l
+			break
+		}
+	}
+
+	switch {
+	case tomlTableRegex.MatchString(line), tomlKeyRegex.MatchString(line):
+		return decodeTOML
+	case strings.HasPrefix(line, "{"), strings.HasPrefix(line, "["):
+		return decodeJSON
+	default:
+		return decodeYAML
+	}
+}
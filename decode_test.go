@@ -0,0 +1,82 @@
+package config
+
+import (
+	"testing"
+)
+
+type decodeTest struct {
+	Str string `json:"str"`
+}
+
+func TestSetConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Json extension",
+			file: "config.json",
+			data: `{"str": "json"}`,
+			want: "json",
+		},
+		{
+			name:    "Json extension with yaml data",
+			file:    "config.json",
+			data:    "str: json",
+			wantErr: true,
+		},
+		{
+			name: "Yaml extension",
+			file: "config.yaml",
+			data: "str: yaml",
+			want: "yaml",
+		},
+		{
+			name: "Yml extension",
+			file: "CONFIG.YML",
+			data: "str: yml",
+			want: "yml",
+		},
+		{
+			name: "Toml extension",
+			file: "config.toml",
+			data: `str = "toml"`,
+			want: "toml",
+		},
+		{
+			name: "Sniff toml key",
+			data: "# comment\n\nstr = \"toml\"",
+			want: "toml",
+		},
+		{
+			name: "Sniff toml table",
+			data: "[nested]\nfield = \"toml\"\n",
+			want: "",
+		},
+		{
+			name: "Sniff json",
+			data: ` {"str": "json"}`,
+			want: "json",
+		},
+		{
+			name: "Sniff yaml",
+			data: "str: yaml",
+			want: "yaml",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeTest{}
+			err := setConfig(tt.file, tt.data, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Str != tt.want {
+				t.Errorf("setConfig() = %v, want %v", got.Str, tt.want)
+			}
+		})
+	}
+}
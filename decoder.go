@@ -0,0 +1,19 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/skos-ninja/config-loader/pkg/parser"
+)
+
+// Decoder is implemented by field types that decode themselves from the raw string value of a file, env or flag tag
+type Decoder = parser.Decoder
+
+// DecoderFunc decodes a raw string value into a value of the type it is registered for
+type DecoderFunc = parser.DecoderFunc
+
+// RegisterDecoder registers the decoder used for fields of the type when set from a file, env or flag tag.
+// This allows types from other packages that don't implement Decoder or encoding.TextUnmarshaler to be used.
+func RegisterDecoder(t reflect.Type, fn DecoderFunc) {
+	parser.RegisterDecoder(t, fn)
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// loadConfigDir loads every supported config file in the directory in lexical order.
+// Hidden files, sub directories and files without a known extension are skipped.
+func loadConfigDir(ctx context.Context, dir string, config interface{}, o *options) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return ErrConfigNotFound{dir, err}
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if strings.HasPrefix(name, ".") {
+			continue
+		}
+		if _, ok := decoders[strings.ToLower(filepath.Ext(name))]; !ok {
+			continue
+		}
+
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return ErrConfigNotFound{path, err}
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return ErrConfigNotFound{path, err}
+		}
+
+		err = setConfigFile(ctx, path, string(b), config, o)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
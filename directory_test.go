@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestLoadConfigDir(t *testing.T) {
+	type Test struct {
+		DB    string `json:"db"`
+		Cache string `json:"cache"`
+		Owner string `json:"owner"`
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "10-db.json"), `{"db": "db", "owner": "db"}`)
+	writeFile(t, filepath.Join(dir, "20-cache.yaml"), "cache: cache\nowner: cache\n")
+	writeFile(t, filepath.Join(dir, "README.md"), "not a config")
+	writeFile(t, filepath.Join(dir, ".30-hidden.json"), `{"owner": "hidden"}`)
+	if err := os.Mkdir(filepath.Join(dir, "40-directory.json"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{
+			name: "Directory",
+		},
+		{
+			name: "Legacy lookup",
+			opts: []Option{WithLegacyLookup()},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			Init(cmd)
+			if err := cmd.ParseFlags([]string{"--config", dir}); err != nil {
+				t.Fatal(err)
+			}
+
+			var loaded []string
+			cfg := &Test{}
+			opts := append(tt.opts, WithFileLoaded(func(path string) {
+				loaded = append(loaded, path)
+			}))
+			err := Load(cmd, cfg, opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := &Test{DB: "db", Cache: "cache", Owner: "cache"}
+			if !reflect.DeepEqual(cfg, want) {
+				t.Errorf("Load() = %+v, want %+v", cfg, want)
+			}
+			wantLoaded := []string{filepath.Join(dir, "10-db.json"), filepath.Join(dir, "20-cache.yaml")}
+			if !reflect.DeepEqual(loaded, wantLoaded) {
+				t.Errorf("Load() loaded = %v, want %v", loaded, wantLoaded)
+			}
+		})
+	}
+}
+
+func TestLoadDirectorySource(t *testing.T) {
+	type Test struct {
+		Host string `configmap:"db-host"`
+		Port int    `configmap:"db-port" env:"TEST_DIRECTORY_SOURCE_PORT"`
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "db-host"), "localhost\n")
+	writeFile(t, filepath.Join(dir, "db-port"), "5432")
+	t.Setenv("TEST_DIRECTORY_SOURCE_PORT", "6543")
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+
+	// Env is applied after the directory by default
+	cfg := &Test{}
+	err := Load(cmd, cfg, WithDirectory("configmap", dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Test{Host: "localhost", Port: 6543}); *cfg != want {
+		t.Errorf("Load() = %+v, want %+v", *cfg, want)
+	}
+
+	cfg = &Test{}
+	err = Load(cmd, cfg, WithDirectory("configmap", dir), WithPrecedence(SourceEnv, "configmap"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Test{Host: "localhost", Port: 5432}); *cfg != want {
+		t.Errorf("Load() with precedence = %+v, want %+v", *cfg, want)
+	}
+
+	// The source is only known to loads passed the option
+	err = Load(cmd, cfg, WithPrecedence("configmap"))
+	if !errors.As(err, &ErrUnknownSource{}) {
+		t.Errorf("Load() error = %v, want ErrUnknownSource", err)
+	}
+}
@@ -0,0 +1,101 @@
+package config
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// searchExtensions are the extensions tried for each search path in order
+var searchExtensions = []string{".json", ".yaml", ".yml", ".toml"}
+
+// InitOption configures the behaviour of Init
+type InitOption func(*initOptions)
+
+type initOptions struct {
+	searchPaths []string
+	searchAll   bool
+}
+
+var initOpts = &initOptions{}
+
+func newInitOptions(opts []InitOption) *initOptions {
+	o := &initOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithSearchPaths sets the paths searched for a config file when --config is not passed.
+// Each path is given without an extension and is tried with .json, .yaml, .yml and .toml in order.
+// Paths earlier in the list take precedence and passing no paths disables the search.
+func WithSearchPaths(paths ...string) InitOption {
+	return func(o *initOptions) {
+		if paths == nil {
+			paths = []string{}
+		}
+		o.searchPaths = paths
+	}
+}
+
+// WithSearchAll loads every config file found in the search paths instead of just the first.
+// The files are merged with those earlier in the search paths taking precedence.
+func WithSearchAll() InitOption {
+	return func(o *initOptions) {
+		o.searchAll = true
+	}
+}
+
+// DefaultSearchPaths returns the paths searched for an app's config file when none are set with WithSearchPaths
+func DefaultSearchPaths(app string) []string {
+	paths := []string{filepath.Join(".", app)}
+
+	home, _ := os.UserHomeDir()
+	xdg := os.Getenv("XDG_CONFIG_HOME")
+	if xdg == "" && home != "" {
+		xdg = filepath.Join(home, ".config")
+	}
+	if xdg != "" {
+		paths = append(paths, filepath.Join(xdg, app, "config"))
+	}
+	if home != "" {
+		paths = append(paths, filepath.Join(home, "."+app, "config"))
+	}
+
+	return append(paths, filepath.Join("/etc", app, "config"))
+}
+
+// searchConfigFiles returns the config files found in the search paths in the order they should be loaded
+func searchConfigFiles(app string, o *initOptions) ([]string, error) {
+	paths := o.searchPaths
+	if paths == nil && app != "" {
+		paths = DefaultSearchPaths(app)
+	}
+
+	var found []string
+	for _, path := range paths {
+		for _, ext := range searchExtensions {
+			info, err := os.Stat(path + ext)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			if !info.Mode().IsRegular() {
+				continue
+			}
+
+			if !o.searchAll {
+				return []string{path + ext}, nil
+			}
+			// Files found first take precedence so are loaded last
+			found = append([]string{path + ext}, found...)
+		}
+	}
+
+	return found, nil
+}
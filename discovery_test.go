@@ -0,0 +1,104 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDefaultSearchPaths(t *testing.T) {
+	t.Setenv("HOME", "/home/test")
+
+	t.Run("XDG config home", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "/xdg")
+		want := []string{"app", "/xdg/app/config", "/home/test/.app/config", "/etc/app/config"}
+		if got := DefaultSearchPaths("app"); !reflect.DeepEqual(got, want) {
+			t.Errorf("DefaultSearchPaths() = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("XDG config home not set", func(t *testing.T) {
+		t.Setenv("XDG_CONFIG_HOME", "")
+		want := []string{"app", "/home/test/.config/app/config", "/home/test/.app/config", "/etc/app/config"}
+		if got := DefaultSearchPaths("app"); !reflect.DeepEqual(got, want) {
+			t.Errorf("DefaultSearchPaths() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestSearchConfigFiles(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first")
+	second := filepath.Join(dir, "second")
+	writeFile(t, first+".yaml", "str: first")
+	writeFile(t, first+".toml", `str = "first-toml"`)
+	writeFile(t, second+".json", `{"str": "second"}`)
+	if err := os.Mkdir(filepath.Join(dir, "directory.json"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		opts []InitOption
+		want []string
+	}{
+		{
+			name: "First found",
+			opts: []InitOption{WithSearchPaths(filepath.Join(dir, "missing"), filepath.Join(dir, "directory"), second, first)},
+			want: []string{second + ".json"},
+		},
+		{
+			name: "All found",
+			opts: []InitOption{WithSearchPaths(first, filepath.Join(dir, "missing"), second), WithSearchAll()},
+			want: []string{second + ".json", first + ".toml", first + ".yaml"},
+		},
+		{
+			name: "Search disabled",
+			opts: []InitOption{WithSearchPaths()},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := searchConfigFiles("test", newInitOptions(tt.opts))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("searchConfigFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSearchedConfig(t *testing.T) {
+	type Test struct {
+		Str string `json:"str"`
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "app.yaml"), "str: searched")
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd, WithSearchPaths(filepath.Join(dir, "app")))
+
+	var loaded []string
+	cfg := &Test{}
+	err := Load(cmd, cfg, WithFileLoaded(func(path string) {
+		loaded = append(loaded, path)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Str != "searched" {
+		t.Errorf("Load() = %v, want %v", cfg.Str, "searched")
+	}
+	want := []string{filepath.Join(dir, "app.yaml")}
+	if !reflect.DeepEqual(loaded, want) {
+		t.Errorf("Load() loaded = %v, want %v", loaded, want)
+	}
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/skos-ninja/config-loader/pkg/dotenv"
+	"github.com/skos-ninja/config-loader/pkg/parser"
+)
+
+// withEnvFiles returns a context where env variables are also looked up from the dotenv files.
+// The process environment takes precedence unless the env file override option is set.
+func withEnvFiles(ctx context.Context, paths []string, o *options) (context.Context, error) {
+	if len(paths) == 0 {
+		return ctx, nil
+	}
+
+	// Later files take precedence over earlier ones
+	vars := map[string]string{}
+	for _, path := range paths {
+		env, err := dotenv.ReadFile(path)
+		if errors.As(err, &dotenv.ErrSyntax{}) {
+			return nil, err
+		}
+		if err != nil {
+			return nil, ErrConfigNotFound{path, err}
+		}
+		for k, v := range env {
+			vars[k] = v
+		}
+	}
+
+	environ := parser.GetEnviron(ctx)
+	ctx = parser.WithEnviron(ctx, func() []string {
+		env := environ()
+		for k, v := range vars {
+			env = append(env, k+"="+v)
+		}
+		return env
+	})
+
+	fileLookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	lookup := parser.GetEnvLookup(ctx)
+	if o.envOverride {
+		return parser.WithEnvLookup(ctx, layerEnvLookups(fileLookup, lookup)), nil
+	}
+	return parser.WithEnvLookup(ctx, layerEnvLookups(lookup, fileLookup)), nil
+}
+
+// layerEnvLookups returns a lookup that uses the top lookup's variables over the bottom's.
+// A variable set in the top layer also hides the bottom layer's variable with or without the _FILE suffix
+// so a value in one layer doesn't conflict with a file in the other.
+func layerEnvLookups(top, bottom func(string) (string, bool)) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		if v, ok := top(name); ok {
+			return v, true
+		}
+
+		other := name + parser.EnvFileSuffix
+		if strings.HasSuffix(name, parser.EnvFileSuffix) {
+			other = strings.TrimSuffix(name, parser.EnvFileSuffix)
+		}
+		if _, ok := top(other); ok {
+			return "", false
+		}
+
+		return bottom(name)
+	}
+}
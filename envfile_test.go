@@ -0,0 +1,138 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/skos-ninja/config-loader/pkg/dotenv"
+	"github.com/spf13/cobra"
+)
+
+func TestLoadEnvFiles(t *testing.T) {
+	type Test struct {
+		Both     string `env:"TEST_ENV_FILE_BOTH"`
+		FileOnly string `env:"TEST_ENV_FILE_ONLY"`
+		Later    string `env:"TEST_ENV_FILE_LATER"`
+		Password string `env:"TEST_ENV_FILE_PASSWORD"`
+		URL      string `json:"url"`
+	}
+
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.env")
+	second := filepath.Join(dir, "second.env")
+	writeFile(t, first, "TEST_ENV_FILE_BOTH=file\nexport TEST_ENV_FILE_ONLY='file only'\nTEST_ENV_FILE_LATER=first\n")
+	secret := filepath.Join(dir, "secret")
+	writeFile(t, secret, "file secret\n")
+	writeFile(t, second, "TEST_ENV_FILE_LATER=second\nTEST_ENV_FILE_PASSWORD_FILE="+secret+"\n")
+	config := filepath.Join(dir, "config.yaml")
+	writeFile(t, config, "url: http://${TEST_ENV_FILE_ONLY}/\n")
+	t.Setenv("TEST_ENV_FILE_BOTH", "process")
+	t.Setenv("TEST_ENV_FILE_PASSWORD", "process")
+
+	tests := []struct {
+		name string
+		opts []Option
+		want Test
+	}{
+		{
+			name: "Process env takes precedence",
+			want: Test{Both: "process", FileOnly: "file only", Later: "second", Password: "process", URL: "http://file only/"},
+		},
+		{
+			name: "Env file override",
+			opts: []Option{WithEnvFileOverride()},
+			want: Test{Both: "file", FileOnly: "file only", Later: "second", Password: "file secret", URL: "http://file only/"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			Init(cmd)
+			err := cmd.ParseFlags([]string{"--config", config, "--env-file", first + "," + second})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &Test{}
+			err = Load(cmd, cfg, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *cfg != tt.want {
+				t.Errorf("Load() = %+v, want %+v", *cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvFilesErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := filepath.Join(dir, "invalid.env")
+	writeFile(t, invalid, "INVALID\n")
+
+	tests := []struct {
+		name string
+		file string
+		want error
+	}{
+		{
+			name: "Missing file",
+			file: filepath.Join(dir, "missing.env"),
+			want: ErrConfigNotFound{},
+		},
+		{
+			name: "Invalid file",
+			file: invalid,
+			want: dotenv.ErrSyntax{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			Init(cmd, WithSearchPaths())
+			err := cmd.ParseFlags([]string{"--env-file", tt.file})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = Load(cmd, &struct{}{})
+			target := reflect.New(reflect.TypeOf(tt.want)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("Load() error = %v, want %T", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadEnvFileIndexes(t *testing.T) {
+	type Server struct {
+		Host string `env:"HOST"`
+	}
+	type Test struct {
+		Servers []Server `env:"TEST_ENV_FILE_SERVERS"`
+	}
+
+	path := filepath.Join(t.TempDir(), ".env")
+	writeFile(t, path, "TEST_ENV_FILE_SERVERS_0_HOST=file\n")
+	t.Setenv("TEST_ENV_FILE_SERVERS_1_HOST", "process")
+
+	cmd := &cobra.Command{Use: "test"}
+	Init(cmd)
+	err := cmd.ParseFlags([]string{"--env-file", path})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &Test{}
+	err = Load(cmd, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{Servers: []Server{{Host: "file"}, {Host: "process"}}}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("Load() = %+v, want %+v", cfg, want)
+	}
+}
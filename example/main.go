@@ -20,7 +20,9 @@ var (
 
 func init() {
 	config.Init(cmd)
-	cmd.Flags().String("CONFIG_FLAG", cfg.Flag, "Config flag")
+	if err := config.RegisterFlags(cmd, cfg); err != nil {
+		panic(err)
+	}
 }
 
 func main() {
@@ -31,7 +33,7 @@ func main() {
 
 type exampleConfig struct {
 	Env  string `env:"CONFIG_ENV"`
-	Flag string `flag:"CONFIG_FLAG"`
+	Flag string `flag:"CONFIG_FLAG" usage:"Config flag"`
 }
 
 func runE(cmd *cobra.Command, args []string) error {
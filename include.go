@@ -0,0 +1,179 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/skos-ninja/config-loader/pkg/parser"
+)
+
+// maxIncludeDepth is the maximum number of nested includes allowed from a config
+const maxIncludeDepth = 10
+
+// includeKeys are the keys in a config that list other config files to include
+var includeKeys = []string{"$include", "include"}
+
+type ErrIncludeCycle struct {
+	path string
+}
+
+func (e ErrIncludeCycle) Error() string {
+	return fmt.Sprintf("Config include cycle found: %s", e.path)
+}
+
+type ErrIncludeDepth struct {
+	path string
+}
+
+func (e ErrIncludeDepth) Error() string {
+	return fmt.Sprintf("Config include depth of %d exceeded: %s", maxIncludeDepth, e.path)
+}
+
+type ErrInvalidInclude struct {
+	path string
+}
+
+func (e ErrInvalidInclude) Error() string {
+	return fmt.Sprintf("Config include must be a string or list of strings: %s", e.path)
+}
+
+// setConfigIncludes decodes the config data and sets any files it includes onto the config before its own values.
+// Included paths are relative to the directory of the including file or the working directory if it's not a file.
+// depth is the number of includes the config is nested in, which is 0 for a config that isn't included.
+func setConfigIncludes(ctx context.Context, name string, configStr string, config interface{}, o *options, parents []string, depth int) error {
+	v, err := decodeConfig(name, configStr)
+	if err != nil {
+		return err
+	}
+
+	if o.interpolate {
+		v, err = interpolate(v, parser.GetEnvLookup(ctx))
+		if err != nil {
+			return err
+		}
+	}
+
+	includes, err := popIncludes(name, v, reflect.TypeOf(config))
+	if err != nil {
+		return err
+	}
+
+	for _, include := range includes {
+		path := include
+		if !filepath.IsAbs(path) && name != "" {
+			path = filepath.Join(filepath.Dir(name), path)
+		}
+
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		for _, parent := range parents {
+			if parent == abs {
+				return ErrIncludeCycle{path}
+			}
+		}
+		if depth >= maxIncludeDepth {
+			return ErrIncludeDepth{path}
+		}
+
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return ErrConfigNotFound{path, err}
+		}
+
+		err = setConfigIncludes(ctx, path, string(b), config, o, append(parents, abs), depth+1)
+		if err != nil {
+			return err
+		}
+
+		if o.fileLoaded != nil {
+			o.fileLoaded(path)
+		}
+	}
+
+	return setDecodedConfig(v, config)
+}
+
+// popIncludes removes the include keys from the decoded config and returns the paths listed.
+// Keys the config struct has a field for are left as values for that field.
+func popIncludes(name string, v interface{}, t reflect.Type) ([]string, error) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil, nil
+	}
+
+	var includes []string
+	for _, key := range includeKeys {
+		value, ok := m[key]
+		if !ok || hasJSONField(t, key) {
+			continue
+		}
+		delete(m, key)
+
+		switch value := value.(type) {
+		case string:
+			includes = append(includes, value)
+		case []interface{}:
+			for _, e := range value {
+				s, ok := e.(string)
+				if !ok {
+					return nil, ErrInvalidInclude{name}
+				}
+				includes = append(includes, s)
+			}
+		default:
+			return nil, ErrInvalidInclude{name}
+		}
+	}
+
+	return includes, nil
+}
+
+// hasJSONField returns if json would decode the key into a field of the struct type
+func hasJSONField(t reflect.Type, key string) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+
+		// Embedded structs without a name have their fields promoted
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if hasJSONField(ft, key) {
+					return true
+				}
+				continue
+			}
+		}
+		if !sf.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		if strings.EqualFold(name, key) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,164 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+type includeTest struct {
+	A      string   `json:"a"`
+	B      string   `json:"b"`
+	C      string   `json:"c"`
+	Append []string `json:"append" merge:"append"`
+}
+
+func TestConfigIncludes(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "common"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(dir, "common", "base.yaml"), "$include: nested.toml\na: base\nb: base\nappend: [base]\n")
+	writeFile(t, filepath.Join(dir, "common", "nested.toml"), "a = \"nested\"\nc = \"nested\"\nappend = [\"nested\"]\n")
+	writeFile(t, filepath.Join(dir, "other.json"), `{"b": "other"}`)
+	writeFile(t, filepath.Join(dir, "service.json"), `{"include": ["common/base.yaml", "other.json"], "a": "service", "append": ["service"]}`)
+
+	var loaded []string
+	o := newOptions([]Option{WithFileLoaded(func(path string) {
+		loaded = append(loaded, path)
+	})})
+
+	path := filepath.Join(dir, "service.json")
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := &includeTest{}
+	err = setConfigFile(context.Background(), path, string(b), got, o)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &includeTest{A: "service", B: "other", C: "nested", Append: []string{"nested", "base", "service"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("setConfigFile() = %+v, want %+v", got, want)
+	}
+	wantLoaded := []string{
+		filepath.Join(dir, "common", "nested.toml"),
+		filepath.Join(dir, "common", "base.yaml"),
+		filepath.Join(dir, "other.json"),
+		path,
+	}
+	if !reflect.DeepEqual(loaded, wantLoaded) {
+		t.Errorf("setConfigFile() loaded = %v, want %v", loaded, wantLoaded)
+	}
+}
+
+func TestConfigIncludesErrors(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "cycle-a.json"), `{"$include": "cycle-b.json"}`)
+	writeFile(t, filepath.Join(dir, "cycle-b.json"), `{"$include": "cycle-a.json"}`)
+	writeFile(t, filepath.Join(dir, "self.json"), `{"$include": "./self.json"}`)
+	writeFile(t, filepath.Join(dir, "invalid.json"), `{"$include": 1}`)
+	writeFile(t, filepath.Join(dir, "invalid-list.json"), `{"$include": ["a.json", 1]}`)
+	writeFile(t, filepath.Join(dir, "missing.json"), `{"$include": "not-found.json"}`)
+	// depth-0.json includes a chain 11 deep and depth-1.json one that is exactly the limit
+	for i := 0; i <= maxIncludeDepth; i++ {
+		writeFile(t, filepath.Join(dir, fmt.Sprintf("depth-%d.json", i)), fmt.Sprintf(`{"$include": "depth-%d.json"}`, i+1))
+	}
+	writeFile(t, filepath.Join(dir, fmt.Sprintf("depth-%d.json", maxIncludeDepth+1)), `{}`)
+
+	tests := []struct {
+		name string
+		file string
+		want error
+	}{
+		{
+			name: "Cycle",
+			file: "cycle-a.json",
+			want: ErrIncludeCycle{},
+		},
+		{
+			name: "Self include",
+			file: "self.json",
+			want: ErrIncludeCycle{},
+		},
+		{
+			name: "Depth",
+			file: "depth-0.json",
+			want: ErrIncludeDepth{},
+		},
+		{
+			name: "Invalid include",
+			file: "invalid.json",
+			want: ErrInvalidInclude{},
+		},
+		{
+			name: "Invalid include list",
+			file: "invalid-list.json",
+			want: ErrInvalidInclude{},
+		},
+		{
+			name: "Missing include",
+			file: "missing.json",
+			want: ErrConfigNotFound{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			b, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = setConfigFile(context.Background(), path, string(b), &includeTest{}, newOptions(nil))
+			target := reflect.New(reflect.TypeOf(tt.want)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("setConfigFile() error = %v, want %T", err, tt.want)
+			}
+		})
+	}
+
+	t.Run("Depth limit", func(t *testing.T) {
+		path := filepath.Join(dir, "depth-1.json")
+		b, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		err = setConfigFile(context.Background(), path, string(b), &includeTest{}, newOptions(nil))
+		if err != nil {
+			t.Errorf("setConfigFile() error = %v, want nil", err)
+		}
+	})
+}
+
+func TestConfigIncludeField(t *testing.T) {
+	type Test struct {
+		Name    string   `json:"name"`
+		Include []string `json:"include"`
+	}
+
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "base.json"), `{"name": "base"}`)
+	path := filepath.Join(dir, "config.json")
+	data := `{"$include": "base.json", "include": ["*.go"]}`
+
+	// The include key is a value for the struct's field while $include is still a directive
+	got := &Test{}
+	err := setConfigFile(context.Background(), path, data, got, newOptions(nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{Name: "base", Include: []string{"*.go"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("setConfigFile() = %+v, want %+v", got, want)
+	}
+}
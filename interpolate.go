@@ -0,0 +1,139 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ErrInterpolation struct {
+	variable string
+	message  string
+}
+
+func (e ErrInterpolation) Error() string {
+	return fmt.Sprintf("Environment variable %s: %s", e.variable, e.message)
+}
+
+type ErrInvalidInterpolation struct {
+	value string
+}
+
+func (e ErrInvalidInterpolation) Error() string {
+	return fmt.Sprintf("Invalid environment variable reference: %s", e.value)
+}
+
+// interpolate expands environment variable references in every string value of the decoded config
+func interpolate(v interface{}, lookup func(string) (string, bool)) (interface{}, error) {
+	switch v := v.(type) {
+	case string:
+		return interpolateString(v, lookup)
+	case map[string]interface{}:
+		for k, e := range v {
+			e, err := interpolate(e, lookup)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = e
+		}
+		return v, nil
+	case []interface{}:
+		for i, e := range v {
+			e, err := interpolate(e, lookup)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = e
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
+
+// interpolateString expands ${VAR}, ${VAR:-default} and ${VAR:?message} references with $$ used for a literal $.
+// Any other $ is left as is.
+func interpolateString(s string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				return "", ErrInvalidInterpolation{s}
+			}
+
+			value, err := expandReference(s[i+2:end], lookup)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(value)
+			i = end
+		default:
+			b.WriteByte('$')
+		}
+	}
+
+	return b.String(), nil
+}
+
+// closingBrace returns the index of the brace closing a reference starting at start allowing for nested references
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+
+	return -1
+}
+
+// expandReference returns the value of the reference between ${ and }
+func expandReference(ref string, lookup func(string) (string, bool)) (string, error) {
+	name, op, arg := ref, "", ""
+	if i := strings.Index(ref, ":"); i >= 0 && i+1 < len(ref) && (ref[i+1] == '-' || ref[i+1] == '?') {
+		name, op, arg = ref[:i], ref[i:i+2], ref[i+2:]
+	}
+	if name == "" || strings.ContainsAny(name, "${}:") {
+		return "", ErrInvalidInterpolation{"${" + ref + "}"}
+	}
+
+	value, _ := lookup(name)
+	if value != "" {
+		return value, nil
+	}
+
+	switch op {
+	case ":-":
+		return interpolateString(arg, lookup)
+	case ":?":
+		message, err := interpolateString(arg, lookup)
+		if err != nil {
+			return "", err
+		}
+		if message == "" {
+			message = "not set"
+		}
+		return "", ErrInterpolation{name, message}
+	default:
+		return "", nil
+	}
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInterpolateString(t *testing.T) {
+	vars := map[string]string{
+		"HOST":  "localhost",
+		"PORT":  "8080",
+		"EMPTY": "",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr error
+	}{
+		{name: "No references", value: "plain", want: "plain"},
+		{name: "Variable", value: "${HOST}", want: "localhost"},
+		{name: "Variables in text", value: "http://${HOST}:${PORT}/", want: "http://localhost:8080/"},
+		{name: "Missing variable", value: "a${MISSING}b", want: "ab"},
+		{name: "Default when missing", value: "${MISSING:-default}", want: "default"},
+		{name: "Default when empty", value: "${EMPTY:-default}", want: "default"},
+		{name: "Default not used when set", value: "${HOST:-default}", want: "localhost"},
+		{name: "Nested default", value: "${MISSING:-${HOST}:${PORT}}", want: "localhost:8080"},
+		{name: "Error not used when set", value: "${PORT:?port is required}", want: "8080"},
+		{name: "Error when missing", value: "${MISSING:?port is required}", wantErr: ErrInterpolation{"MISSING", "port is required"}},
+		{name: "Error when empty", value: "${EMPTY:?}", wantErr: ErrInterpolation{"EMPTY", "not set"}},
+		{name: "Escaped", value: "$${HOST} costs $$5", want: "${HOST} costs $5"},
+		{name: "Bare dollar", value: "$HOST $ 5$", want: "$HOST $ 5$"},
+		{name: "Unclosed reference", value: "${HOST", wantErr: ErrInvalidInterpolation{"${HOST"}},
+		{name: "Empty reference", value: "${}", wantErr: ErrInvalidInterpolation{"${}"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := interpolateString(tt.value, lookup)
+			if err != tt.wantErr {
+				t.Fatalf("interpolateString() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("interpolateString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInterpolateConfig(t *testing.T) {
+	type Test struct {
+		URL   string            `json:"url"`
+		Hosts []string          `json:"hosts"`
+		Map   map[string]string `json:"map"`
+		Port  int               `json:"port"`
+	}
+	t.Setenv("TEST_INTERPOLATE_HOST", "localhost")
+
+	data := "url: http://${TEST_INTERPOLATE_HOST}/\nhosts: [\"${TEST_INTERPOLATE_HOST}\"]\nmap:\n  key: ${TEST_INTERPOLATE_MISSING:-default}\nport: 80\n"
+
+	t.Run("Enabled", func(t *testing.T) {
+		got := &Test{}
+		err := setConfigFile(context.Background(), "", data, got, newOptions(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &Test{URL: "http://localhost/", Hosts: []string{"localhost"}, Map: map[string]string{"key": "default"}, Port: 80}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("setConfigFile() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		got := &Test{}
+		err := setConfigFile(context.Background(), "", data, got, newOptions([]Option{WithoutInterpolation()}))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := &Test{URL: "http://${TEST_INTERPOLATE_HOST}/", Hosts: []string{"${TEST_INTERPOLATE_HOST}"}, Map: map[string]string{"key": "${TEST_INTERPOLATE_MISSING:-default}"}, Port: 80}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("setConfigFile() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("Required variable missing", func(t *testing.T) {
+		err := setConfigFile(context.Background(), "", `{"url": "${TEST_INTERPOLATE_MISSING:?url is required}"}`, &Test{}, newOptions(nil))
+		if !errors.As(err, &ErrInterpolation{}) {
+			t.Errorf("setConfigFile() error = %v, want ErrInterpolation", err)
+		}
+	})
+}
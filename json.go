@@ -2,13 +2,42 @@ package config
 
 import (
 	"encoding/json"
+	"errors"
+	"io"
+	"strings"
 )
 
+// decodeJSON decodes json keeping numbers as json.Number so they are not rounded
+func decodeJSON(configStr string) (interface{}, error) {
+	if configStr == "" {
+		return nil, nil
+	}
+
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(configStr))
+	d.UseNumber()
+	err := d.Decode(&v)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only a single value is allowed in the data
+	if err := d.Decode(&struct{}{}); !errors.Is(err, io.EOF) {
+		return nil, errors.New("invalid character after top-level value")
+	}
+
+	return v, nil
+}
+
 func setJSONConfig(configStr string, config interface{}) error {
 	if configStr == "" {
 		return nil
 	}
 
+	// Decoding onto the existing values merges structs and maps by key and replaces slices
+	fields := clearSliceFields(config)
+	defer restoreSliceFields(fields)
+
 	err := json.Unmarshal([]byte(configStr), config)
 	if err != nil {
 		return err
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"reflect"
+)
+
+const (
+	mergeTagName = "merge"
+	// mergeAppend appends slices from later configs onto earlier ones instead of replacing them
+	mergeAppend = "append"
+)
+
+type sliceField struct {
+	value    reflect.Value
+	previous reflect.Value
+	append   bool
+}
+
+// clearSliceFields finds every slice and sets it to nil so that after decoding we can tell if the config
+// contained a value for it. Decoding onto an existing slice reuses its elements so slices of structs would
+// otherwise keep fields from earlier configs.
+func clearSliceFields(config interface{}) []sliceField {
+	rv := reflect.ValueOf(config)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil
+	}
+
+	return clearSliceFieldsValue(rv.Elem())
+}
+
+func clearSliceFieldsValue(v reflect.Value) []sliceField {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []sliceField
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		if !field.CanSet() {
+			continue
+		}
+
+		if field.Kind() == reflect.Slice {
+			previous := reflect.Zero(field.Type())
+			if !field.IsNil() {
+				previous = reflect.MakeSlice(field.Type(), field.Len(), field.Len())
+				reflect.Copy(previous, field)
+			}
+			fields = append(fields, sliceField{
+				value:    field,
+				previous: previous,
+				append:   v.Type().Field(i).Tag.Get(mergeTagName) == mergeAppend,
+			})
+			field.Set(reflect.Zero(field.Type()))
+			continue
+		}
+
+		fields = append(fields, clearSliceFieldsValue(field)...)
+	}
+
+	return fields
+}
+
+// restoreSliceFields restores the previous values of slices not in the config and prepends them onto
+// decoded values of slices tagged with merge:"append"
+func restoreSliceFields(fields []sliceField) {
+	for _, f := range fields {
+		if f.value.IsNil() {
+			f.value.Set(f.previous)
+			continue
+		}
+
+		if f.append {
+			f.value.Set(reflect.AppendSlice(f.previous, f.value))
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+type mergeNested struct {
+	Field  string   `json:"field"`
+	Other  string   `json:"other"`
+	Append []string `json:"append" merge:"append"`
+}
+
+type mergeServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type mergeTest struct {
+	Str     string            `json:"str"`
+	Map     map[string]string `json:"map"`
+	Replace []string          `json:"replace"`
+	Append  []string          `json:"append" merge:"append"`
+	Servers []mergeServer     `json:"servers"`
+	Nested  mergeNested       `json:"nested"`
+	Ptr     *mergeNested      `json:"ptr"`
+}
+
+func TestMergeConfigs(t *testing.T) {
+	tests := []struct {
+		name    string
+		configs []string
+		want    mergeTest
+	}{
+		{
+			name:    "Single config",
+			configs: []string{`{"str": "a", "append": ["a"]}`},
+			want: mergeTest{
+				Str:    "a",
+				Append: []string{"default", "a"},
+			},
+		},
+		{
+			name: "Later configs override earlier ones",
+			configs: []string{
+				`{"str": "a", "nested": {"field": "a", "other": "a"}}`,
+				`{"str": "b", "nested": {"field": "b"}}`,
+			},
+			want: mergeTest{
+				Str:    "b",
+				Append: []string{"default"},
+				Nested: mergeNested{Field: "b", Other: "a"},
+			},
+		},
+		{
+			name: "Maps are merged by key",
+			configs: []string{
+				`{"map": {"a": "a", "b": "a"}}`,
+				`{"map": {"b": "b", "c": "b"}}`,
+			},
+			want: mergeTest{
+				Map:    map[string]string{"a": "a", "b": "b", "c": "b"},
+				Append: []string{"default"},
+			},
+		},
+		{
+			name: "Slices are replaced by default",
+			configs: []string{
+				`{"replace": ["a", "b"]}`,
+				`{"replace": ["c"]}`,
+			},
+			want: mergeTest{
+				Replace: []string{"c"},
+				Append:  []string{"default"},
+			},
+		},
+		{
+			name: "Slices of structs are replaced",
+			configs: []string{
+				`{"servers": [{"host": "a", "port": 1}, {"host": "b", "port": 2}]}`,
+				`{"servers": [{"host": "c"}]}`,
+			},
+			want: mergeTest{
+				Servers: []mergeServer{{Host: "c"}},
+				Append:  []string{"default"},
+			},
+		},
+		{
+			name: "Slices not in a later config are kept",
+			configs: []string{
+				`{"replace": ["a"], "servers": [{"host": "a", "port": 1}]}`,
+				`{"str": "b"}`,
+			},
+			want: mergeTest{
+				Str:     "b",
+				Replace: []string{"a"},
+				Servers: []mergeServer{{Host: "a", Port: 1}},
+				Append:  []string{"default"},
+			},
+		},
+		{
+			name: "Slices tagged with append are appended",
+			configs: []string{
+				`{"append": ["a", "b"], "nested": {"append": ["a"]}, "ptr": {"append": ["a"]}}`,
+				`{"str": "b"}`,
+				`{"append": ["c"], "nested": {"append": ["b"]}, "ptr": {"append": ["b"]}}`,
+			},
+			want: mergeTest{
+				Str:    "b",
+				Append: []string{"default", "a", "b", "c"},
+				Nested: mergeNested{Append: []string{"a", "b"}},
+				Ptr:    &mergeNested{Append: []string{"a", "b"}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := mergeTest{Append: []string{"default"}}
+			for _, c := range tt.configs {
+				err := setJSONConfig(c, &got)
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setJSONConfig() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeConfigsError(t *testing.T) {
+	got := mergeTest{Append: []string{"default"}}
+	err := setJSONConfig(`{"append": "not-a-slice"}`, &got)
+	if err == nil {
+		t.Fatal("setJSONConfig() expected error")
+	}
+	if !reflect.DeepEqual(got.Append, []string{"default"}) {
+		t.Errorf("setJSONConfig() = %v, want %v", got.Append, []string{"default"})
+	}
+}
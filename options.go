@@ -0,0 +1,129 @@
+package config
+
+import (
+	"context"
+
+	"github.com/skos-ninja/config-loader/pkg/parser"
+)
+
+// Sources that can be used when setting the precedence of a load
+const (
+	// SourceConfig is the config data passed via the config flag
+	SourceConfig = "config"
+	// SourceFile is the values of fields tagged with file
+	SourceFile = "file"
+	// SourceEnv is the values of fields tagged with env
+	SourceEnv = "env"
+	// SourceFlag is the values of fields tagged with flag
+	SourceFlag = "flag"
+)
+
+// Option configures the behaviour of Load
+type Option func(*options)
+
+type options struct {
+	precedence    []string
+	precedenceSet bool
+	directories   []directorySource
+	legacyLookup  bool
+	fileLoaded    func(path string)
+	interpolate   bool
+	envOverride   bool
+	autoNames     bool
+	autoPrefix    string
+}
+
+type directorySource struct {
+	source string
+	dir    string
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		precedence:  append([]string{SourceConfig}, parser.GetPrecedence(context.Background())...),
+		interpolate: true,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	// Directories are applied after file tags unless the precedence is set
+	if !o.precedenceSet {
+		for i := len(o.directories) - 1; i >= 0; i-- {
+			o.precedence = insertAfter(o.precedence, SourceFile, o.directories[i].source)
+		}
+	}
+
+	return o
+}
+
+// insertAfter inserts the source after the existing source or at the end if it is not found
+func insertAfter(precedence []string, after string, source string) []string {
+	for i, s := range precedence {
+		if s == after {
+			result := append([]string{}, precedence[:i+1]...)
+			result = append(result, source)
+			return append(result, precedence[i+1:]...)
+		}
+	}
+
+	return append(precedence, source)
+}
+
+// WithPrecedence sets the order sources are applied to the config from lowest to highest precedence.
+// Sources not listed are not loaded. The default is config < file < env < flag with the struct defaults lowest.
+func WithPrecedence(sources ...string) Option {
+	return func(o *options) {
+		o.precedence = sources
+		o.precedenceSet = true
+	}
+}
+
+// WithLegacyLookup restores the previous behaviour for config values without a scheme.
+// The value is tried as a file path, an upper case env name and a flag name with any that can't be found ignored.
+func WithLegacyLookup() Option {
+	return func(o *options) {
+		o.legacyLookup = true
+	}
+}
+
+// WithFileLoaded sets a func that is called with the path of each config file loaded,
+// including any found by searching when --config is not passed.
+func WithFileLoaded(fn func(path string)) Option {
+	return func(o *options) {
+		o.fileLoaded = fn
+	}
+}
+
+// WithoutInterpolation disables expanding environment variable references in config string values
+func WithoutInterpolation() Option {
+	return func(o *options) {
+		o.interpolate = false
+	}
+}
+
+// WithEnvFileOverride makes values from --env-file files take precedence over the process environment
+func WithEnvFileOverride() Option {
+	return func(o *options) {
+		o.envOverride = true
+	}
+}
+
+// WithAutoNames derives env and flag names for fields without env or flag tags from their path in the struct.
+// Database.MaxConns is read from the env variable PREFIX_DATABASE_MAX_CONNS and the flag --database-max-conns,
+// with the prefix left off env names when it is empty. Fields can opt out with env:"-" or flag:"-".
+func WithAutoNames(prefix string) Option {
+	return func(o *options) {
+		o.autoNames = true
+		o.autoPrefix = prefix
+	}
+}
+
+// WithDirectory reads fields tagged with the source's name from a directory where each file name is a key,
+// such as a mounted kubernetes ConfigMap or Secret. Every value is read from the same update of the volume.
+// The source is applied after file tags unless it is listed in WithPrecedence.
+func WithDirectory(source string, dir string) Option {
+	return func(o *options) {
+		o.directories = append(o.directories, directorySource{source: source, dir: dir})
+	}
+}
@@ -0,0 +1,220 @@
+package dotenv
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type ErrSyntax struct {
+	path    string
+	line    int
+	message string
+}
+
+func (e ErrSyntax) Error() string {
+	if e.path != "" {
+		return fmt.Sprintf("Invalid dotenv in %s at line %d: %s", e.path, e.line, e.message)
+	}
+	return fmt.Sprintf("Invalid dotenv at line %d: %s", e.line, e.message)
+}
+
+// ReadFile parses the dotenv file at the path with syntax errors including the path
+func ReadFile(path string) (map[string]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := Parse(string(b))
+	var serr ErrSyntax
+	if errors.As(err, &serr) {
+		serr.path = path
+		return nil, serr
+	}
+
+	return env, err
+}
+
+// Parse parses dotenv data into a map of variables.
+//
+// Each line is a KEY=value pair optionally prefixed with export. Values can be unquoted, single quoted or double quoted.
+// Unquoted values are trimmed and end at a # preceded by whitespace.
+// Single quoted values are used as is while double quoted values support \n, \r, \t, \", \\ and \$ escapes.
+// Quoted values can span multiple lines. Blank lines and lines starting with # are ignored.
+func Parse(data string) (map[string]string, error) {
+	p := &dotenvParser{data: strings.ReplaceAll(data, "\r\n", "\n"), line: 1}
+	env := map[string]string{}
+
+	for {
+		p.skipBlank()
+		if p.done() {
+			return env, nil
+		}
+
+		key, value, err := p.parseLine()
+		if err != nil {
+			return nil, err
+		}
+		env[key] = value
+	}
+}
+
+type dotenvParser struct {
+	data string
+	pos  int
+	line int
+}
+
+func (p *dotenvParser) done() bool {
+	return p.pos >= len(p.data)
+}
+
+func (p *dotenvParser) errorf(format string, a ...interface{}) error {
+	return ErrSyntax{line: p.line, message: fmt.Sprintf(format, a...)}
+}
+
+// skipBlank skips whitespace, empty lines and comment lines
+func (p *dotenvParser) skipBlank() {
+	for !p.done() {
+		switch p.data[p.pos] {
+		case ' ', '\t':
+			p.pos++
+		case '\n':
+			p.pos++
+			p.line++
+		case '#':
+			p.skipLine()
+		default:
+			return
+		}
+	}
+}
+
+// skipLine skips to the start of the next line
+func (p *dotenvParser) skipLine() {
+	for !p.done() && p.data[p.pos] != '\n' {
+		p.pos++
+	}
+	if !p.done() {
+		p.pos++
+		p.line++
+	}
+}
+
+func (p *dotenvParser) skipSpaces() {
+	for !p.done() && (p.data[p.pos] == ' ' || p.data[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) parseLine() (string, string, error) {
+	key := p.parseKey()
+	if key == "export" {
+		p.skipSpaces()
+		if !p.done() && p.data[p.pos] != '=' {
+			key = p.parseKey()
+		}
+	}
+	if key == "" {
+		return "", "", p.errorf("expected variable name")
+	}
+
+	p.skipSpaces()
+	if p.done() || p.data[p.pos] != '=' {
+		return "", "", p.errorf("expected = after %s", key)
+	}
+	p.pos++
+	p.skipSpaces()
+
+	var value string
+	var err error
+	if !p.done() && (p.data[p.pos] == '\'' || p.data[p.pos] == '"') {
+		value, err = p.parseQuoted(p.data[p.pos])
+		if err != nil {
+			return "", "", err
+		}
+
+		// Only a comment can follow a quoted value
+		p.skipSpaces()
+		if !p.done() && p.data[p.pos] != '\n' && p.data[p.pos] != '#' {
+			return "", "", p.errorf("unexpected character after quoted value for %s", key)
+		}
+		p.skipLine()
+	} else {
+		value = p.parseUnquoted()
+	}
+
+	return key, value, nil
+}
+
+func (p *dotenvParser) parseKey() string {
+	start := p.pos
+	for !p.done() {
+		c := p.data[p.pos]
+		if c == '_' || c == '.' || c == '-' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (p.pos > start && c >= '0' && c <= '9') {
+			p.pos++
+			continue
+		}
+		break
+	}
+
+	return p.data[start:p.pos]
+}
+
+func (p *dotenvParser) parseUnquoted() string {
+	start := p.pos
+	for !p.done() && p.data[p.pos] != '\n' {
+		if p.data[p.pos] == '#' && p.pos > start && (p.data[p.pos-1] == ' ' || p.data[p.pos-1] == '\t') {
+			break
+		}
+		p.pos++
+	}
+	value := strings.TrimSpace(p.data[start:p.pos])
+	p.skipLine()
+
+	return value
+}
+
+func (p *dotenvParser) parseQuoted(quote byte) (string, error) {
+	startLine := p.line
+	p.pos++
+
+	var b strings.Builder
+	for !p.done() {
+		c := p.data[p.pos]
+		p.pos++
+
+		switch {
+		case c == quote:
+			return b.String(), nil
+		case c == '\n':
+			p.line++
+			b.WriteByte(c)
+		case c == '\\' && quote == '"' && !p.done():
+			e := p.data[p.pos]
+			p.pos++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(e)
+			default:
+				if e == '\n' {
+					p.line++
+				}
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", ErrSyntax{line: startLine, message: "unterminated quoted value"}
+}
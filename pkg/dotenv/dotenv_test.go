@@ -0,0 +1,125 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name: "Empty",
+			data: "",
+			want: map[string]string{},
+		},
+		{
+			name: "Unquoted values",
+			data: "KEY=value\nSPACED = spaced value  \nEMPTY=\nURL=http://host/#anchor\n",
+			want: map[string]string{"KEY": "value", "SPACED": "spaced value", "EMPTY": "", "URL": "http://host/#anchor"},
+		},
+		{
+			name: "Comments",
+			data: "# comment\n  # indented comment\nKEY=value # inline comment\n\n\nOTHER=other\n",
+			want: map[string]string{"KEY": "value", "OTHER": "other"},
+		},
+		{
+			name: "Export",
+			data: "export KEY=value\nexport\tOTHER=other\nexport=export\n",
+			want: map[string]string{"KEY": "value", "OTHER": "other", "export": "export"},
+		},
+		{
+			name: "Single quoted",
+			data: `KEY='single # quoted \n $value' # comment`,
+			want: map[string]string{"KEY": `single # quoted \n $value`},
+		},
+		{
+			name: "Double quoted escapes",
+			data: `KEY="line\nline\ttab \"quoted\" \\ \$ \x"`,
+			want: map[string]string{"KEY": "line\nline\ttab \"quoted\" \\ $ \\x"},
+		},
+		{
+			name: "Multi-line quoted values",
+			data: "KEY=\"-----BEGIN KEY-----\nabc\n-----END KEY-----\"\nSINGLE='a\nb'\nOTHER=other",
+			want: map[string]string{"KEY": "-----BEGIN KEY-----\nabc\n-----END KEY-----", "SINGLE": "a\nb", "OTHER": "other"},
+		},
+		{
+			name: "Windows line endings",
+			data: "KEY=value\r\nOTHER=other\r\n",
+			want: map[string]string{"KEY": "value", "OTHER": "other"},
+		},
+		{
+			name: "Later values override earlier ones",
+			data: "KEY=first\nKEY=second\n",
+			want: map[string]string{"KEY": "second"},
+		},
+		{
+			name:    "Missing equals",
+			data:    "KEY=value\nINVALID\n",
+			wantErr: ErrSyntax{line: 2, message: "expected = after INVALID"},
+		},
+		{
+			name:    "Missing name",
+			data:    "KEY=value\n\n=value\n",
+			wantErr: ErrSyntax{line: 3, message: "expected variable name"},
+		},
+		{
+			name:    "Unterminated quote",
+			data:    "KEY=value\nQUOTED=\"value\nOTHER=other\n",
+			wantErr: ErrSyntax{line: 2, message: "unterminated quoted value"},
+		},
+		{
+			name:    "Text after quote",
+			data:    "KEY=\"a\nb\" c\n",
+			wantErr: ErrSyntax{line: 2, message: "unexpected character after quoted value for KEY"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.data)
+			if err != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	err := os.WriteFile(path, []byte("KEY=value\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"KEY": "value"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadFile() = %v, want %v", got, want)
+	}
+
+	_, err = ReadFile(filepath.Join(t.TempDir(), "missing"))
+	if err == nil {
+		t.Error("ReadFile() expected error")
+	}
+
+	invalid := filepath.Join(t.TempDir(), "invalid.env")
+	err = os.WriteFile(invalid, []byte("KEY=value\nINVALID\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ReadFile(invalid)
+	if want := (ErrSyntax{path: invalid, line: 2, message: "expected = after INVALID"}); err != want {
+		t.Errorf("ReadFile() error = %v, want %v", err, want)
+	}
+}
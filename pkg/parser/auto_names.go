@@ -0,0 +1,95 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"unicode"
+)
+
+var autoNamesKey contextKey = "autoNames"
+
+// WithAutoNames returns a context where env and flag names are derived from the path of fields without env or flag tags.
+// Database.MaxConns is read from the env variable PREFIX_DATABASE_MAX_CONNS and the flag --database-max-conns.
+// Nested structs with a prefix tag use it in place of their field's name and embedded structs are flattened.
+// Fields can opt out with env:"-" or flag:"-".
+func WithAutoNames(ctx context.Context, prefix string) context.Context {
+	ctx = context.WithValue(ctx, autoNamesKey, true)
+	if prefix = strings.TrimSuffix(prefix, "_"); prefix != "" {
+		ctx = withDerivedPrefix(ctx, envTagName, prefix+"_")
+	}
+
+	return ctx
+}
+
+// hasAutoNames returns if names are derived for fields without tags
+func hasAutoNames(ctx context.Context) bool {
+	v, _ := ctx.Value(autoNamesKey).(bool)
+	return v
+}
+
+// fieldName returns the name of the field for the tag with any prefix applied.
+// Fields without the tag have their name derived when WithAutoNames is used.
+func fieldName(ctx context.Context, tag string, sf reflect.StructField) (string, bool) {
+	name := sf.Tag.Get(tag)
+	if name == "-" {
+		return "", false
+	}
+	if name != "" {
+		return getTagPrefix(ctx, tag).tagged + name, true
+	}
+	if !hasAutoNames(ctx) || !sf.IsExported() {
+		return "", false
+	}
+
+	switch tag {
+	case envTagName:
+		return getTagPrefix(ctx, tag).derived + envName(sf.Name), true
+	case flagTagName:
+		return getTagPrefix(ctx, tag).derived + flagName(sf.Name), true
+	}
+
+	return "", false
+}
+
+// envName converts a Go name to an env variable name such as MaxConns to MAX_CONNS
+func envName(name string) string {
+	return strings.ToUpper(strings.Join(splitWords(name), "_"))
+}
+
+// flagName converts a Go name to a flag name such as MaxConns to max-conns
+func flagName(name string) string {
+	return strings.ToLower(strings.Join(splitWords(name), "-"))
+}
+
+// splitWords splits a Go name into words keeping initialisms together so HTTPServerID is HTTP, Server and ID
+func splitWords(name string) []string {
+	runes := []rune(name)
+
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		prev, cur := runes[i-1], runes[i]
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+
+		lowerToUpper := unicode.IsUpper(cur) && (unicode.IsLower(prev) || unicode.IsDigit(prev))
+		endOfInitialism := unicode.IsUpper(cur) && unicode.IsUpper(prev) && unicode.IsLower(next)
+		if cur == '_' || lowerToUpper || endOfInitialism {
+			if i > start {
+				words = append(words, string(runes[start:i]))
+			}
+			start = i
+			if cur == '_' {
+				start = i + 1
+			}
+		}
+	}
+	if start < len(runes) {
+		words = append(words, string(runes[start:]))
+	}
+
+	return words
+}
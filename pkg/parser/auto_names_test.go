@@ -0,0 +1,112 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDerivedNames(t *testing.T) {
+	tests := []struct {
+		name     string
+		wantEnv  string
+		wantFlag string
+	}{
+		{name: "Host", wantEnv: "HOST", wantFlag: "host"},
+		{name: "MaxConns", wantEnv: "MAX_CONNS", wantFlag: "max-conns"},
+		{name: "HTTPServer", wantEnv: "HTTP_SERVER", wantFlag: "http-server"},
+		{name: "ServerID", wantEnv: "SERVER_ID", wantFlag: "server-id"},
+		{name: "Port2", wantEnv: "PORT2", wantFlag: "port2"},
+		{name: "TLS2Cert", wantEnv: "TLS2_CERT", wantFlag: "tls2-cert"},
+		{name: "Max_Conns", wantEnv: "MAX_CONNS", wantFlag: "max-conns"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := envName(tt.name); got != tt.wantEnv {
+				t.Errorf("envName() = %v, want %v", got, tt.wantEnv)
+			}
+			if got := flagName(tt.name); got != tt.wantFlag {
+				t.Errorf("flagName() = %v, want %v", got, tt.wantFlag)
+			}
+		})
+	}
+}
+
+func TestAutoNamesParse(t *testing.T) {
+	type Database struct {
+		MaxConns int
+		Host     string `env:"DB_HOST"`
+	}
+	type Server struct {
+		Port int
+	}
+	type Test struct {
+		TestEmbedded
+		Name     string
+		Database Database
+		Replica  *Database `prefix:"REPLICA_"`
+		Servers  []Server
+		Ignored  string `env:"-" flag:"-"`
+		EnvOnly  string `flag:"-"`
+		internal string
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	setFlag(cmd, flag{name: "database-max-conns", value: "20", kind: reflect.Int64})
+	setFlag(cmd, flag{name: "ignored", value: "flag", kind: reflect.String})
+	ctx := withTestEnv(c.GetContextWithCmd(cmd), map[string]string{
+		"APP_NAME":                "name",
+		"APP_DATABASE_MAX_CONNS":  "10",
+		"DB_HOST":                 "tagged",
+		"APP_REPLICA_MAX_CONNS":   "5",
+		"APP_SERVERS_0_PORT":      "80",
+		"APP_SERVERS_1_PORT":      "81",
+		"APP_IGNORED":             "env",
+		"APP_ENV_ONLY":            "env",
+		"APP_INTERNAL":            "env",
+		"NAME":                    "tagged",
+		"APP_TEST_EMBEDDED_NAME":  "not flattened",
+		"APP_DATABASE_DB_HOST":    "prefixed tag",
+		"REPLICA_MAX_CONNS":       "unprefixed",
+		"APP_SERVERS_0_SERVERS_0": "nested",
+	})
+	ctx = WithAutoNames(ctx, "APP")
+
+	got := &Test{}
+	err := ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{
+		TestEmbedded: TestEmbedded{Name: "tagged"},
+		Name:         "name",
+		Database:     Database{MaxConns: 20, Host: "tagged"},
+		Replica:      &Database{MaxConns: 5},
+		Servers:      []Server{{Port: 80}, {Port: 81}},
+		EnvOnly:      "env",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStruct() = %+v, want %+v", got, want)
+	}
+}
+
+func TestAutoNamesDisabled(t *testing.T) {
+	type Test struct {
+		Name string
+	}
+
+	ctx := withTestEnv(context.Background(), map[string]string{"NAME": "name"})
+	got := &Test{}
+	err := ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "" {
+		t.Errorf("ParseStruct() = %+v, want untagged field unset", got)
+	}
+}
@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+type ErrInvalidValue struct {
+	field string
+	value string
+	err   error
+}
+
+func (e ErrInvalidValue) Error() string {
+	return fmt.Sprintf("Invalid value %s for field %s: %v", e.value, e.field, e.err)
+}
+
+func (e ErrInvalidValue) Unwrap() error {
+	return e.err
+}
+
+// Decoder is implemented by types that decode themselves from the raw string value of a field
+type Decoder interface {
+	Decode(value string) error
+}
+
+// DecoderFunc decodes a raw string value into a value of the type it is registered for
+type DecoderFunc func(value string) (interface{}, error)
+
+var (
+	decoderType         = reflect.TypeOf((*Decoder)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	decodersMu sync.RWMutex
+	decoders   = map[reflect.Type]DecoderFunc{
+		reflect.TypeOf(net.IPNet{}):      decodeIPNet,
+		reflect.TypeOf(url.URL{}):        decodeURL,
+		reflect.TypeOf(&regexp.Regexp{}): decodeRegexp,
+		reflect.TypeOf(mail.Address{}):   decodeMailAddress,
+	}
+)
+
+// RegisterDecoder registers the decoder used for fields of the type.
+// This allows types that can't implement Decoder, such as those from other packages, to be decoded.
+// Registered decoders take precedence over the Decoder and encoding.TextUnmarshaler interfaces.
+func RegisterDecoder(t reflect.Type, fn DecoderFunc) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+
+	decoders[t] = fn
+}
+
+// getDecoder returns the decoder registered for the type
+func getDecoder(t reflect.Type) (DecoderFunc, bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+
+	fn, ok := decoders[t]
+	return fn, ok
+}
+
+// isDecodable returns if values of the type are decoded from a string rather than by their kind
+func isDecodable(t reflect.Type) bool {
+	if _, ok := getDecoder(t); ok {
+		return true
+	}
+
+	pt := reflect.PtrTo(t)
+	return pt.Implements(decoderType) || pt.Implements(textUnmarshalerType)
+}
+
+// decodeValue decodes the string into v using the registered decoder for its type or the type's Decoder or
+// encoding.TextUnmarshaler implementation. v must be addressable.
+func decodeValue(s string, v reflect.Value) error {
+	if fn, ok := getDecoder(v.Type()); ok {
+		value, err := fn(s)
+		if err != nil {
+			return err
+		}
+
+		rv := reflect.ValueOf(value)
+		if !rv.IsValid() || !rv.Type().AssignableTo(v.Type()) {
+			return fmt.Errorf("decoder for %s returned %T", v.Type(), value)
+		}
+		v.Set(rv)
+		return nil
+	}
+
+	switch d := v.Addr().Interface().(type) {
+	case Decoder:
+		return d.Decode(s)
+	case encoding.TextUnmarshaler:
+		return d.UnmarshalText([]byte(s))
+	}
+
+	return errUnsupportedType
+}
+
+// setDecoded sets the field by decoding the field parser's string value for the tag
+func setDecoded(ctx context.Context, f FieldParser, tag string, field reflect.Value, sf reflect.StructField) error {
+	value, err := f.GetString(ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	// Decode into a new value so the field is left unchanged on error
+	v := reflect.New(field.Type()).Elem()
+	if err := decodeValue(value, v); err != nil {
+		return ErrInvalidValue{field: sf.Name, value: value, err: err}
+	}
+
+	field.Set(v)
+	return nil
+}
+
+// decodeIPNet decodes a CIDR such as 10.0.0.0/8 into its network
+func decodeIPNet(value string) (interface{}, error) {
+	_, n, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return *n, nil
+}
+
+// decodeURL decodes a URL which must not be empty
+func decodeURL(value string) (interface{}, error) {
+	if value == "" {
+		return nil, errors.New("empty url")
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return *u, nil
+}
+
+// decodeRegexp compiles a regular expression
+func decodeRegexp(value string) (interface{}, error) {
+	return regexp.Compile(value)
+}
+
+// decodeMailAddress decodes an RFC 5322 address such as "Name <name@example.com>"
+func decodeMailAddress(value string) (interface{}, error) {
+	a, err := mail.ParseAddress(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return *a, nil
+}
@@ -0,0 +1,238 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+
+	"github.com/spf13/cobra"
+)
+
+type testLevel int
+
+func (l *testLevel) UnmarshalText(text []byte) error {
+	switch string(text) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	default:
+		return fmt.Errorf("unknown level %s", text)
+	}
+	return nil
+}
+
+type testUpper struct {
+	Value string
+}
+
+func (u *testUpper) Decode(value string) error {
+	u.Value = strings.ToUpper(value)
+	return nil
+}
+
+// testPoint has no methods to show types that can't be modified being decoded with a registered decoder
+type testPoint struct {
+	X, Y string
+}
+
+func init() {
+	RegisterDecoder(reflect.TypeOf(testPoint{}), func(value string) (interface{}, error) {
+		xy := strings.SplitN(value, ",", 2)
+		if len(xy) != 2 {
+			return nil, errors.New("point must be x,y")
+		}
+		return testPoint{X: xy[0], Y: xy[1]}, nil
+	})
+}
+
+func TestDecoderParse(t *testing.T) {
+	type Test struct {
+		Level  testLevel            `env:"TEST_DECODER_LEVEL"`
+		Upper  testUpper            `env:"TEST_DECODER_UPPER"`
+		Point  testPoint            `env:"TEST_DECODER_POINT"`
+		Ptr    *testPoint           `env:"TEST_DECODER_POINT"`
+		Unset  *testLevel           `env:"TEST_DECODER_UNSET"`
+		Levels []testLevel          `env:"TEST_DECODER_LEVELS"`
+		Uppers map[string]testUpper `env:"TEST_DECODER_UPPERS"`
+		Nested struct {
+			Level testLevel `env:"TEST_DECODER_LEVEL"`
+		}
+		Default testUpper
+	}
+
+	setEnv(t, env{name: "TEST_DECODER_LEVEL", value: "info"})
+	setEnv(t, env{name: "TEST_DECODER_UPPER", value: "loud"})
+	setEnv(t, env{name: "TEST_DECODER_POINT", value: "1,2"})
+	setEnv(t, env{name: "TEST_DECODER_LEVELS", value: "debug,info"})
+	setEnv(t, env{name: "TEST_DECODER_UPPERS", value: "a=x,b=y"})
+
+	got := &Test{Default: testUpper{Value: "default"}}
+	err := ParseStruct(context.Background(), got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{
+		Level:   1,
+		Upper:   testUpper{Value: "LOUD"},
+		Point:   testPoint{X: "1", Y: "2"},
+		Ptr:     &testPoint{X: "1", Y: "2"},
+		Levels:  []testLevel{0, 1},
+		Uppers:  map[string]testUpper{"a": {Value: "X"}, "b": {Value: "Y"}},
+		Default: testUpper{Value: "default"},
+	}
+	want.Nested.Level = 1
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStruct() = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecoderParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		s     interface{}
+	}{
+		{
+			name:  "TextUnmarshaler",
+			value: "loud",
+			s: &struct {
+				Level testLevel `env:"TEST_DECODER_ERROR"`
+			}{},
+		},
+		{
+			name:  "Registered decoder",
+			value: "1",
+			s: &struct {
+				Point *testPoint `env:"TEST_DECODER_ERROR"`
+			}{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, env{name: "TEST_DECODER_ERROR", value: tt.value})
+			err := ParseStruct(context.Background(), tt.s, false)
+			if !errors.As(err, &ErrInvalidValue{}) {
+				t.Errorf("ParseStruct() error = %v, want ErrInvalidValue", err)
+			}
+		})
+	}
+}
+
+func TestNetworkTypesParse(t *testing.T) {
+	type Test struct {
+		IP       net.IP         `env:"TEST_NETWORK_IP"`
+		IPNet    net.IPNet      `env:"TEST_NETWORK_IPNET"`
+		IPNetPtr *net.IPNet     `env:"TEST_NETWORK_IPNET"`
+		Addr     netip.Addr     `env:"TEST_NETWORK_ADDR"`
+		Prefix   netip.Prefix   `env:"TEST_NETWORK_PREFIX"`
+		AddrPort netip.AddrPort `env:"TEST_NETWORK_ADDRPORT"`
+		URL      *url.URL       `env:"TEST_NETWORK_URL"`
+		Regexp   *regexp.Regexp `env:"TEST_NETWORK_REGEXP"`
+		Mail     *mail.Address  `env:"TEST_NETWORK_MAIL"`
+		Unset    *url.URL       `env:"TEST_NETWORK_UNSET"`
+		FlagIP   net.IP         `flag:"test-network-ip"`
+		FlagNet  *net.IPNet     `flag:"test-network-ipnet"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().IP("test-network-ip", nil, "")
+	cmd.Flags().IPNet("test-network-ipnet", net.IPNet{}, "")
+	err := cmd.Flags().Parse([]string{"--test-network-ip", "192.168.0.1", "--test-network-ipnet", "192.168.0.0/16"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := c.GetContextWithCmd(cmd)
+
+	setEnv(t, env{name: "TEST_NETWORK_IP", value: "10.0.0.1"})
+	setEnv(t, env{name: "TEST_NETWORK_IPNET", value: "10.0.0.0/8"})
+	setEnv(t, env{name: "TEST_NETWORK_ADDR", value: "::1"})
+	setEnv(t, env{name: "TEST_NETWORK_PREFIX", value: "10.1.0.0/16"})
+	setEnv(t, env{name: "TEST_NETWORK_ADDRPORT", value: "127.0.0.1:8080"})
+	setEnv(t, env{name: "TEST_NETWORK_URL", value: "https://example.com/path?q=1"})
+	setEnv(t, env{name: "TEST_NETWORK_REGEXP", value: "^[a-z]+$"})
+	setEnv(t, env{name: "TEST_NETWORK_MAIL", value: "Alerts <alerts@example.com>"})
+
+	got := &Test{}
+	err = ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, ipNet, _ := net.ParseCIDR("10.0.0.0/8")
+	_, flagNet, _ := net.ParseCIDR("192.168.0.0/16")
+	u, _ := url.Parse("https://example.com/path?q=1")
+	want := &Test{
+		IP:       net.ParseIP("10.0.0.1"),
+		IPNet:    *ipNet,
+		IPNetPtr: ipNet,
+		Addr:     netip.MustParseAddr("::1"),
+		Prefix:   netip.MustParsePrefix("10.1.0.0/16"),
+		AddrPort: netip.MustParseAddrPort("127.0.0.1:8080"),
+		URL:      u,
+		Regexp:   regexp.MustCompile("^[a-z]+$"),
+		Mail:     &mail.Address{Name: "Alerts", Address: "alerts@example.com"},
+		FlagIP:   net.ParseIP("192.168.0.1"),
+		FlagNet:  flagNet,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStruct() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNetworkTypesParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		s     interface{}
+	}{
+		{name: "IP", value: "10.0.0", s: &struct {
+			V net.IP `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "IPNet", value: "10.0.0.1", s: &struct {
+			V *net.IPNet `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "Addr", value: "localhost", s: &struct {
+			V netip.Addr `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "Prefix", value: "10.0.0.0/33", s: &struct {
+			V netip.Prefix `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "AddrPort", value: "127.0.0.1", s: &struct {
+			V netip.AddrPort `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "URL", value: "http://[::1", s: &struct {
+			V *url.URL `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "Empty URL", value: "", s: &struct {
+			V *url.URL `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "Regexp", value: "[a-z", s: &struct {
+			V *regexp.Regexp `env:"TEST_NETWORK_ERROR"`
+		}{}},
+		{name: "Mail", value: "not an address", s: &struct {
+			V *mail.Address `env:"TEST_NETWORK_ERROR"`
+		}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, env{name: "TEST_NETWORK_ERROR", value: tt.value, forceEmpty: tt.value == ""})
+			err := ParseStruct(context.Background(), tt.s, false)
+			got := ErrInvalidValue{}
+			if !errors.As(err, &got) || got.field != "V" {
+				t.Errorf("ParseStruct() error = %v, want ErrInvalidValue for field V", err)
+			}
+		})
+	}
+}
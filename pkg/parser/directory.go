@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// directoryDataLink is the symlink kubernetes swaps atomically to point at the latest version of a volume's files
+const directoryDataLink = "..data"
+
+type ErrInvalidDirectoryKey struct {
+	key string
+}
+
+func (e ErrInvalidDirectoryKey) Error() string {
+	return fmt.Sprintf("Invalid directory key: %s", e.key)
+}
+
+// DirectoryParser reads values from a directory where each file name is a key such as a mounted kubernetes ConfigMap or Secret.
+// Values are trimmed of whitespace unless the key has the ,raw suffix.
+//
+// Each ParseStruct call reads every value from the volume's current ..data directory so updates are seen when
+// re-parsed without values from before and after an update being mixed. Use Snapshot to do the same across calls.
+type DirectoryParser struct {
+	Dir string
+}
+
+// Snapshot returns a parser that reads from the volume's current ..data directory so later updates are not mixed in.
+// If the directory has no ..data link the parser is returned unchanged.
+func (p DirectoryParser) Snapshot() (DirectoryParser, error) {
+	dir, err := filepath.EvalSymlinks(filepath.Join(p.Dir, directoryDataLink))
+	if errors.Is(err, fs.ErrNotExist) {
+		return p, nil
+	}
+	if err != nil {
+		return p, err
+	}
+
+	return DirectoryParser{Dir: dir}, nil
+}
+
+// withSnapshots returns a context where each DirectoryParser in the precedence reads from a snapshot of its volume
+func withSnapshots(ctx context.Context) (context.Context, error) {
+	for _, tag := range GetPrecedence(ctx) {
+		f, _ := GetFieldParser(ctx, tag)
+		p, ok := f.(DirectoryParser)
+		if !ok {
+			continue
+		}
+
+		snapshot, err := p.Snapshot()
+		if err != nil {
+			return ctx, err
+		}
+		ctx = WithFieldParser(ctx, tag, snapshot)
+	}
+
+	return ctx, nil
+}
+
+// Keys returns the keys in the directory ignoring the .. prefixed files kubernetes uses internally
+func (p DirectoryParser) Keys() ([]string, error) {
+	entries, err := os.ReadDir(p.Dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "..") {
+			continue
+		}
+
+		// Keys are symlinks into ..data so stat to check what they point at
+		info, err := os.Stat(filepath.Join(p.Dir, entry.Name()))
+		if err != nil || info.IsDir() {
+			continue
+		}
+		keys = append(keys, entry.Name())
+	}
+
+	return keys, nil
+}
+
+// path returns the path of the file for a key
+func (p DirectoryParser) path(key string) (string, error) {
+	name := strings.TrimSuffix(key, fileRawOption)
+	if name == "" || strings.HasPrefix(name, "..") || strings.ContainsAny(name, `/\`) {
+		return "", ErrInvalidDirectoryKey{key}
+	}
+
+	return filepath.Join(p.Dir, key), nil
+}
+
+// GetString returns the value of a key as a string
+func (p DirectoryParser) GetString(ctx context.Context, key string) (string, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	return FileParser{}.GetString(ctx, path)
+}
+
+// GetInt returns the value of a key as an integer
+func (p DirectoryParser) GetInt(ctx context.Context, key string) (int64, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return FileParser{}.GetInt(ctx, path)
+}
+
+// GetUint returns the value of a key as an unsigned integer
+func (p DirectoryParser) GetUint(ctx context.Context, key string) (uint64, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return FileParser{}.GetUint(ctx, path)
+}
+
+// GetFloat returns the value of a key as a float
+func (p DirectoryParser) GetFloat(ctx context.Context, key string) (float64, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return FileParser{}.GetFloat(ctx, path)
+}
+
+// GetBoolean returns the value of a key as a boolean
+func (p DirectoryParser) GetBoolean(ctx context.Context, key string) (bool, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	return FileParser{}.GetBoolean(ctx, path)
+}
+
+// GetStringSlice returns the value of a key as a comma separated string slice
+func (p DirectoryParser) GetStringSlice(ctx context.Context, key string) ([]string, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return FileParser{}.GetStringSlice(ctx, path)
+}
+
+// GetDuration returns the value of a key as a duration
+func (p DirectoryParser) GetDuration(ctx context.Context, key string) (time.Duration, error) {
+	path, err := p.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	return FileParser{}.GetDuration(ctx, path)
+}
@@ -0,0 +1,179 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeVolume writes the files into a new version of a kubernetes style volume and atomically swaps ..data to it
+func writeVolume(t *testing.T, dir string, version string, files map[string]string) {
+	versionDir := filepath.Join(dir, version)
+	if err := os.Mkdir(versionDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	for name, value := range files {
+		if err := os.WriteFile(filepath.Join(versionDir, name), []byte(value), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		// Keys link through ..data so only need creating once
+		link := filepath.Join(dir, name)
+		if _, err := os.Lstat(link); os.IsNotExist(err) {
+			if err := os.Symlink(filepath.Join(directoryDataLink, name), link); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	tmp := filepath.Join(dir, "..data_tmp")
+	if err := os.Symlink(version, tmp); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, directoryDataLink)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGetDirectoryString(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	writeVolume(t, dir, "..2021_01_01", map[string]string{"db-host": "localhost\n"})
+	p := DirectoryParser{Dir: dir}
+
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Valid String: db-host",
+			key:     "db-host",
+			want:    "localhost",
+			wantErr: false,
+		},
+		{
+			name:    "Valid Raw String",
+			key:     "db-host" + fileRawOption,
+			want:    "localhost\n",
+			wantErr: false,
+		},
+		{
+			name:    "Invalid Missing Key",
+			key:     "missing",
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Internal Key",
+			key:     directoryDataLink,
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid Path Key",
+			key:     "../db-host",
+			want:    "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := p.GetString(ctx, tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDirectoryKeys(t *testing.T) {
+	dir := t.TempDir()
+	writeVolume(t, dir, "..2021_01_01", map[string]string{"db-host": "localhost", "db-port": "5432"})
+
+	got, err := DirectoryParser{Dir: dir}.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"db-host", "db-port"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+}
+
+func TestDirectoryParse(t *testing.T) {
+	type Test struct {
+		Host string `configmap:"db-host"`
+		Port int    `configmap:"db-port"`
+	}
+
+	dir := t.TempDir()
+	writeVolume(t, dir, "..2021_01_01", map[string]string{"db-host": "localhost", "db-port": "5432"})
+
+	ctx := WithFieldParser(context.Background(), "configmap", DirectoryParser{Dir: dir})
+	ctx = WithPrecedence(ctx, []string{"configmap"})
+
+	original := &Test{}
+	err := ParseStruct(ctx, original, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Test{Host: "localhost", Port: 5432}); *original != want {
+		t.Errorf("parseStruct() = %v, want %v", *original, want)
+	}
+
+	snapshot, err := DirectoryParser{Dir: dir}.Snapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Re-parsing after the volume is updated sees the new values
+	writeVolume(t, dir, "..2021_01_02", map[string]string{"db-host": "remote", "db-port": "6543"})
+	err = ParseStruct(ctx, original, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Test{Host: "remote", Port: 6543}); *original != want {
+		t.Errorf("parseStruct() = %v, want %v", *original, want)
+	}
+
+	// A snapshot keeps reading the version it was taken from
+	err = ParseStruct(WithFieldParser(ctx, "configmap", snapshot), original, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Test{Host: "localhost", Port: 5432}); *original != want {
+		t.Errorf("parseStruct() snapshot = %v, want %v", *original, want)
+	}
+}
+
+func TestDirectorySnapshots(t *testing.T) {
+	dir := t.TempDir()
+	writeVolume(t, dir, "..2021_01_01", map[string]string{"db-host": "localhost"})
+
+	ctx := WithFieldParser(context.Background(), "configmap", DirectoryParser{Dir: dir})
+	ctx = WithPrecedence(ctx, []string{"configmap"})
+	ctx, err := withSnapshots(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An update part way through a parse is not seen until the next parse
+	writeVolume(t, dir, "..2021_01_02", map[string]string{"db-host": "remote"})
+	f, _ := GetFieldParser(ctx, "configmap")
+	got, err := f.GetString(ctx, "db-host")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "localhost" {
+		t.Errorf("GetString() = %v, want %v", got, "localhost")
+	}
+}
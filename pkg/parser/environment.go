@@ -6,10 +6,14 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const envTagName = "env"
 
+// EnvFileSuffix is appended to an environment variable's name to read its value from a file instead
+const EnvFileSuffix = "_FILE"
+
 type ErrEnvVariableNotFound struct {
 	variable string
 }
@@ -18,18 +22,75 @@ func (e ErrEnvVariableNotFound) Error() string {
 	return fmt.Sprintf("Environment variable not found: %s", e.variable)
 }
 
+type ErrEnvVariableConflict struct {
+	variable string
+}
+
+func (e ErrEnvVariableConflict) Error() string {
+	return fmt.Sprintf("Environment variables %s and %s%s are both set", e.variable, e.variable, EnvFileSuffix)
+}
+
+var envLookupKey contextKey = "envLookup"
+
+// WithEnvLookup returns a context where EnvironmentParser looks up variables with the func instead of os.LookupEnv
+func WithEnvLookup(ctx context.Context, lookup func(string) (string, bool)) context.Context {
+	return context.WithValue(ctx, envLookupKey, lookup)
+}
+
+// GetEnvLookup returns the func set on the context for looking up variables or os.LookupEnv if none is set
+func GetEnvLookup(ctx context.Context) func(string) (string, bool) {
+	v := ctx.Value(envLookupKey)
+	if v != nil {
+		return v.(func(string) (string, bool))
+	}
+
+	return os.LookupEnv
+}
+
+var envEnvironKey contextKey = "envEnviron"
+
+// WithEnviron returns a context where the variables are listed with the func instead of os.Environ.
+// The func returns variables in the same KEY=value form as os.Environ and is used to find indexed variables.
+func WithEnviron(ctx context.Context, environ func() []string) context.Context {
+	return context.WithValue(ctx, envEnvironKey, environ)
+}
+
+// GetEnviron returns the func set on the context for listing variables or os.Environ if none is set
+func GetEnviron(ctx context.Context) func() []string {
+	v := ctx.Value(envEnvironKey)
+	if v != nil {
+		return v.(func() []string)
+	}
+
+	return os.Environ
+}
+
 type EnvironmentParser struct {
 }
 
-// GetString returns an environment variable as a string
+// GetString returns an environment variable as a string.
+// If the variable is not set but the variable with a _FILE suffix is, the trimmed contents of that file are returned instead.
 func (e EnvironmentParser) GetString(ctx context.Context, name string) (string, error) {
 	if name != strings.ToUpper(name) {
 		fmt.Printf("WARN: %v is not in upper case. It is recommended all env variables are upper case\n", name)
 	}
 
-	if value, ok := os.LookupEnv(name); ok {
+	lookup := GetEnvLookup(ctx)
+	value, ok := lookup(name)
+	path, fileOk := lookup(name + EnvFileSuffix)
+	if ok && fileOk {
+		return "", ErrEnvVariableConflict{name}
+	}
+	if ok {
 		return value, nil
 	}
+	if fileOk {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(b)), nil
+	}
 
 	return "", ErrEnvVariableNotFound{name}
 }
@@ -49,6 +110,21 @@ func (e EnvironmentParser) GetInt(ctx context.Context, name string) (int64, erro
 	return parseint, nil
 }
 
+// GetUint returns an environment variable as an unsigned integer
+func (e EnvironmentParser) GetUint(ctx context.Context, name string) (uint64, error) {
+	d, err := e.GetString(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	parseuint, err := strconv.ParseUint(d, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseuint, nil
+}
+
 // GetFloat returns an environment variable as a float
 func (e EnvironmentParser) GetFloat(ctx context.Context, name string) (float64, error) {
 	d, err := e.GetString(ctx, name)
@@ -79,6 +155,7 @@ func (e EnvironmentParser) GetBoolean(ctx context.Context, name string) (bool, e
 	return parsebool, nil
 }
 
+// GetStringSlice returns an environment variable as a comma separated string slice
 func (e EnvironmentParser) GetStringSlice(ctx context.Context, name string) ([]string, error) {
 	d, err := e.GetString(ctx, name)
 	if err != nil {
@@ -87,3 +164,18 @@ func (e EnvironmentParser) GetStringSlice(ctx context.Context, name string) ([]s
 
 	return strings.Split(d, ","), nil
 }
+
+// GetDuration returns an environment variable as a duration
+func (e EnvironmentParser) GetDuration(ctx context.Context, name string) (time.Duration, error) {
+	d, err := e.GetString(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+
+	parseduration, err := time.ParseDuration(d)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseduration, nil
+}
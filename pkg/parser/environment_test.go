@@ -3,7 +3,9 @@ package parser
 import (
 	"context"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 type env struct {
@@ -66,6 +68,91 @@ func TestGetEnvString(t *testing.T) {
 	}
 }
 
+func TestGetEnvStringFile(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "secret")
+	err := os.WriteFile(path, []byte("  secret-value\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		variable string
+		envs     []env
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "Valid File",
+			variable: "VALID_SECRET",
+			envs:     []env{{name: "VALID_SECRET_FILE", value: path}},
+			want:     "secret-value",
+			wantErr:  false,
+		},
+		{
+			name:     "Invalid Conflict",
+			variable: "CONFLICT_SECRET",
+			envs:     []env{{name: "CONFLICT_SECRET", value: "value"}, {name: "CONFLICT_SECRET_FILE", value: path}},
+			want:     "",
+			wantErr:  true,
+		},
+		{
+			name:     "Invalid Missing File",
+			variable: "MISSING_SECRET",
+			envs:     []env{{name: "MISSING_SECRET_FILE", value: filepath.Join(dir, "missing")}},
+			want:     "",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, env := range tt.envs {
+				setEnv(t, env)
+			}
+			got, err := e.GetString(ctx, tt.variable)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetEnvConflictError(t *testing.T) {
+	setEnv(t, env{name: "TEST_CONFLICT", value: "value"})
+	setEnv(t, env{name: "TEST_CONFLICT_FILE", value: "/run/secrets/test"})
+	_, err := e.GetString(context.Background(), "TEST_CONFLICT")
+	expectErr := ErrEnvVariableConflict{variable: "TEST_CONFLICT"}
+	if err == nil || err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+}
+
+func TestGetEnvLookup(t *testing.T) {
+	vars := map[string]string{"LOOKUP_ONLY": "lookup"}
+	ctx := WithEnvLookup(context.Background(), func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+	setEnv(t, env{name: "PROCESS_ONLY", value: "process"})
+
+	got, err := e.GetString(ctx, "LOOKUP_ONLY")
+	if err != nil || got != "lookup" {
+		t.Errorf("GetString() = %v, %v, want %v", got, err, "lookup")
+	}
+
+	_, err = e.GetString(ctx, "PROCESS_ONLY")
+	expectErr := ErrEnvVariableNotFound{variable: "PROCESS_ONLY"}
+	if err == nil || err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+}
+
 func TestGetEnvInt(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
@@ -135,6 +222,66 @@ func TestGetEnvInt(t *testing.T) {
 	}
 }
 
+func TestGetEnvUint(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		args    env
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "Valid Uint: 1",
+			args: env{
+				name:  "valid-uint",
+				value: "1",
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "Valid Uint: max",
+			args: env{
+				name:  "valid-max-uint",
+				value: "18446744073709551615",
+			},
+			want:    18446744073709551615,
+			wantErr: false,
+		},
+		{
+			name: "Invalid Uint: -1",
+			args: env{
+				name:  "invalid-negative-uint",
+				value: "-1",
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Invalid Uint: missing",
+			args: env{
+				name:  "invalid-missing",
+				value: "",
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.args)
+			got, err := e.GetUint(ctx, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUint() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetUint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetFloat(t *testing.T) {
 	ctx := context.Background()
 	tests := []struct {
@@ -274,6 +421,66 @@ func TestGetEnvBoolean(t *testing.T) {
 	}
 }
 
+func TestGetEnvDuration(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		args    env
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Valid Duration: 30s",
+			args: env{
+				name:  "valid-duration",
+				value: "30s",
+			},
+			want:    30 * time.Second,
+			wantErr: false,
+		},
+		{
+			name: "Valid Duration: 1h2m",
+			args: env{
+				name:  "valid-compound-duration",
+				value: "1h2m",
+			},
+			want:    time.Hour + 2*time.Minute,
+			wantErr: false,
+		},
+		{
+			name: "Invalid Duration: missing",
+			args: env{
+				name:  "invalid-missing",
+				value: "",
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Invalid Duration: 30",
+			args: env{
+				name:  "invalid-duration-unit",
+				value: "30",
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.args)
+			got, err := e.GetDuration(ctx, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func setEnv(t *testing.T, env env) {
 	// Ignore setting if the value is empty
 	if env.value == "" && !env.forceEmpty {
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const fileTagName = "file"
+
+// fileRawOption is added to a file tag to use the file contents without trimming whitespace
+const fileRawOption = ",raw"
+
+type ErrFileNotFound struct {
+	path string
+}
+
+func (e ErrFileNotFound) Error() string {
+	return fmt.Sprintf("File not found: %s", e.path)
+}
+
+type FileParser struct {
+}
+
+// GetString returns the contents of a file as a string.
+// The contents are trimmed of whitespace unless the path has the ,raw suffix.
+func (p FileParser) GetString(ctx context.Context, path string) (string, error) {
+	raw := strings.HasSuffix(path, fileRawOption)
+	path = strings.TrimSuffix(path, fileRawOption)
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", ErrFileNotFound{path}
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if raw {
+		return string(b), nil
+	}
+
+	return strings.TrimSpace(string(b)), nil
+}
+
+// GetInt returns the contents of a file as an integer
+func (p FileParser) GetInt(ctx context.Context, path string) (int64, error) {
+	d, err := p.GetString(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	parseint, err := strconv.ParseInt(d, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseint, nil
+}
+
+// GetUint returns the contents of a file as an unsigned integer
+func (p FileParser) GetUint(ctx context.Context, path string) (uint64, error) {
+	d, err := p.GetString(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	parseuint, err := strconv.ParseUint(d, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseuint, nil
+}
+
+// GetFloat returns the contents of a file as a float
+func (p FileParser) GetFloat(ctx context.Context, path string) (float64, error) {
+	d, err := p.GetString(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	parsefloat, err := strconv.ParseFloat(d, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return parsefloat, nil
+}
+
+// GetBoolean returns the contents of a file as a boolean
+func (p FileParser) GetBoolean(ctx context.Context, path string) (bool, error) {
+	d, err := p.GetString(ctx, path)
+	if err != nil {
+		return false, err
+	}
+
+	parsebool, err := strconv.ParseBool(d)
+	if err != nil {
+		return false, err
+	}
+
+	return parsebool, nil
+}
+
+// GetStringSlice returns the contents of a file as a comma separated string slice
+func (p FileParser) GetStringSlice(ctx context.Context, path string) ([]string, error) {
+	d, err := p.GetString(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+
+	return strings.Split(d, ","), nil
+}
+
+// GetDuration returns the contents of a file as a duration
+func (p FileParser) GetDuration(ctx context.Context, path string) (time.Duration, error) {
+	d, err := p.GetString(ctx, path)
+	if err != nil {
+		return 0, err
+	}
+
+	parseduration, err := time.ParseDuration(d)
+	if err != nil {
+		return 0, err
+	}
+
+	return parseduration, nil
+}
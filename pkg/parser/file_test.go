@@ -0,0 +1,129 @@
+package parser
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+var fp = FileParser{}
+
+func writeTestFile(t *testing.T, name string, value string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(value), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return path
+}
+
+func TestGetFileNotFound(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "missing")
+	_, err := fp.GetString(context.Background(), path)
+	expectErr := ErrFileNotFound{path: path}
+	if err == nil || err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+}
+
+func TestGetFileString(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name    string
+		value   string
+		raw     bool
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "Valid String: test",
+			value:   "test",
+			want:    "test",
+			wantErr: false,
+		},
+		{
+			name:    "Valid Trimmed String",
+			value:   "\n  -----BEGIN KEY-----\n  \n",
+			want:    "-----BEGIN KEY-----",
+			wantErr: false,
+		},
+		{
+			name:    "Valid Raw String",
+			value:   "-----BEGIN KEY-----\n",
+			raw:     true,
+			want:    "-----BEGIN KEY-----\n",
+			wantErr: false,
+		},
+		{
+			name:    "Valid Empty String",
+			value:   "",
+			want:    "",
+			wantErr: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeTestFile(t, "value", tt.value)
+			if tt.raw {
+				path += fileRawOption
+			}
+			got, err := fp.GetString(ctx, path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetString() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetString() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileParse(t *testing.T) {
+	type Test struct {
+		Str     string   `file:"str"`
+		Inter   int      `file:"int"`
+		Float   float64  `file:"float"`
+		Boolean bool     `file:"bool"`
+		Slice   []string `file:"slice"`
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"str":   "test-value\n",
+		"int":   "100\n",
+		"float": "100.10\n",
+		"bool":  "true\n",
+		"slice": "a,b\n",
+	}
+	for name, value := range files {
+		err := os.WriteFile(filepath.Join(dir, name), []byte(value), 0600)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Tags are resolved relative to the working directory
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	original := &Test{}
+	expected := &Test{Str: "test-value", Inter: 100, Float: 100.10, Boolean: true, Slice: []string{"a", "b"}}
+	err = ParseStruct(context.Background(), original, true)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !reflect.DeepEqual(original, expected) {
+		t.Errorf("parseStruct() = %v, want %v", *original, *expected)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"time"
 
 	c "github.com/skos-ninja/config-loader/pkg/context"
 
@@ -41,7 +43,8 @@ func (p FlagParser) getFlags(ctx context.Context) (*pflag.FlagSet, error) {
 	return flags, nil
 }
 
-// GetString returns a environment variable as a string
+// GetString returns a flag variable as a string.
+// Flags of other types such as IP or IPNet flags return their value formatted as a string.
 func (p FlagParser) GetString(ctx context.Context, name string) (string, error) {
 	flags, err := p.getFlags(ctx)
 	if err != nil {
@@ -49,13 +52,33 @@ func (p FlagParser) GetString(ctx context.Context, name string) (string, error)
 	}
 
 	if flags.Changed(name) {
+		if f := flags.Lookup(name); f.Value.Type() != "string" {
+			return f.Value.String(), nil
+		}
 		return flags.GetString(name)
 	}
 
 	return "", ErrFlagNotFound{name}
 }
 
-// GetInt returns a environment variable as an integer
+// LookupString returns a flag variable as a string, using its default value if it wasn't set.
+// ErrFlagNotFound is only returned when the flag isn't defined.
+func (p FlagParser) LookupString(ctx context.Context, name string) (string, error) {
+	flags, err := p.getFlags(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	f := flags.Lookup(name)
+	if f == nil {
+		return "", ErrFlagNotFound{name}
+	}
+
+	return f.Value.String(), nil
+}
+
+// GetInt returns a flag variable as an integer.
+// Integer flags of any size such as Int or Int32 flags are parsed from their value formatted as a string.
 func (p FlagParser) GetInt(ctx context.Context, name string) (int64, error) {
 	flags, err := p.getFlags(ctx)
 	if err != nil {
@@ -63,13 +86,29 @@ func (p FlagParser) GetInt(ctx context.Context, name string) (int64, error) {
 	}
 
 	if flags.Changed(name) {
-		return flags.GetInt64(name)
+		return strconv.ParseInt(flags.Lookup(name).Value.String(), 10, 64)
 	}
 
 	return 0, ErrFlagNotFound{name}
 }
 
-// GetFloat returns a flag variable as a float
+// GetUint returns a flag variable as an unsigned integer.
+// Unsigned integer flags of any size such as Uint or Uint32 flags are parsed from their value formatted as a string.
+func (p FlagParser) GetUint(ctx context.Context, name string) (uint64, error) {
+	flags, err := p.getFlags(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if flags.Changed(name) {
+		return strconv.ParseUint(flags.Lookup(name).Value.String(), 10, 64)
+	}
+
+	return 0, ErrFlagNotFound{name}
+}
+
+// GetFloat returns a flag variable as a float.
+// Float32 flags are parsed from their value formatted as a string.
 func (p FlagParser) GetFloat(ctx context.Context, name string) (float64, error) {
 	flags, err := p.getFlags(ctx)
 	if err != nil {
@@ -77,7 +116,7 @@ func (p FlagParser) GetFloat(ctx context.Context, name string) (float64, error)
 	}
 
 	if flags.Changed(name) {
-		return flags.GetFloat64(name)
+		return strconv.ParseFloat(flags.Lookup(name).Value.String(), 64)
 	}
 
 	return 0, ErrFlagNotFound{name}
@@ -97,7 +136,8 @@ func (p FlagParser) GetBoolean(ctx context.Context, name string) (bool, error) {
 	return false, ErrFlagNotFound{name}
 }
 
-// GetStringSlice returns a flag variable as a string slice
+// GetStringSlice returns a flag variable as a string slice.
+// Slice flags of other types such as IntSlice or DurationSlice return their values formatted as strings.
 func (p FlagParser) GetStringSlice(ctx context.Context, name string) ([]string, error) {
 	flags, err := p.getFlags(ctx)
 	if err != nil {
@@ -105,8 +145,69 @@ func (p FlagParser) GetStringSlice(ctx context.Context, name string) ([]string,
 	}
 
 	if flags.Changed(name) {
+		if s, ok := flags.Lookup(name).Value.(pflag.SliceValue); ok {
+			return s.GetSlice(), nil
+		}
 		return flags.GetStringSlice(name)
 	}
 
 	return nil, ErrFlagNotFound{name}
 }
+
+// GetDuration returns a flag variable as a duration.
+// Flags of other types such as String flags are parsed from their value formatted as a string.
+func (p FlagParser) GetDuration(ctx context.Context, name string) (time.Duration, error) {
+	flags, err := p.getFlags(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if flags.Changed(name) {
+		if f := flags.Lookup(name); f.Value.Type() != "duration" {
+			return time.ParseDuration(f.Value.String())
+		}
+		return flags.GetDuration(name)
+	}
+
+	return 0, ErrFlagNotFound{name}
+}
+
+// GetStringMap returns a StringToString, StringToInt or StringToInt64 flag variable as a string map.
+// ErrNotMap is returned for String flags so their value is parsed as a map.
+func (p FlagParser) GetStringMap(ctx context.Context, name string) (map[string]string, error) {
+	flags, err := p.getFlags(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !flags.Changed(name) {
+		return nil, ErrFlagNotFound{name}
+	}
+
+	switch flags.Lookup(name).Value.Type() {
+	case "stringToInt":
+		values, err := flags.GetStringToInt(name)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]string, len(values))
+		for k, v := range values {
+			m[k] = strconv.Itoa(v)
+		}
+		return m, nil
+	case "stringToInt64":
+		values, err := flags.GetStringToInt64(name)
+		if err != nil {
+			return nil, err
+		}
+		m := make(map[string]string, len(values))
+		for k, v := range values {
+			m[k] = strconv.FormatInt(v, 10)
+		}
+		return m, nil
+	case "string":
+		return nil, ErrNotMap
+	}
+
+	return flags.GetStringToString(name)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	c "github.com/skos-ninja/config-loader/pkg/context"
 
@@ -20,6 +21,9 @@ type flag struct {
 
 var f = FlagParser{}
 
+// durationKind is used to register duration flags as they share a kind with int64
+const durationKind = reflect.UnsafePointer + 1
+
 func TestGetFlagNotCobra(t *testing.T) {
 	ctx := context.Background()
 	_, err := f.GetString(ctx, "test")
@@ -123,6 +127,16 @@ func TestGetFlagInt(t *testing.T) {
 			want:    -1,
 			wantErr: false,
 		},
+		{
+			name: "Valid Int: Int flag",
+			args: flag{
+				name:  "valid-int-flag",
+				value: "8080",
+				kind:  reflect.Int,
+			},
+			want:    8080,
+			wantErr: false,
+		},
 		{
 			name: "Invalid Int: missing",
 			args: flag{
@@ -149,6 +163,61 @@ func TestGetFlagInt(t *testing.T) {
 	}
 }
 
+func TestGetFlagUint(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	ctx := c.GetContextWithCmd(cmd)
+	tests := []struct {
+		name    string
+		args    flag
+		want    uint64
+		wantErr bool
+	}{
+		{
+			name: "Valid Uint: 1",
+			args: flag{
+				name:  "valid-uint",
+				value: "1",
+				kind:  reflect.Uint64,
+			},
+			want:    1,
+			wantErr: false,
+		},
+		{
+			name: "Valid Uint: Uint32 flag",
+			args: flag{
+				name:  "valid-uint32-flag",
+				value: "4294967295",
+				kind:  reflect.Uint32,
+			},
+			want:    4294967295,
+			wantErr: false,
+		},
+		{
+			name: "Invalid Uint: missing",
+			args: flag{
+				name:  "invalid-missing",
+				value: "",
+				kind:  reflect.Uint64,
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(cmd, tt.args)
+			got, err := f.GetUint(ctx, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUint() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetUint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetFlagFloat(t *testing.T) {
 	cmd := &cobra.Command{Use: "test"}
 	ctx := c.GetContextWithCmd(cmd)
@@ -179,6 +248,16 @@ func TestGetFlagFloat(t *testing.T) {
 			want:    -1,
 			wantErr: false,
 		},
+		{
+			name: "Valid Float: Float32 flag",
+			args: flag{
+				name:  "valid-float32-flag",
+				value: "0.5",
+				kind:  reflect.Float32,
+			},
+			want:    0.5,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -270,16 +349,91 @@ func TestGetFlagBoolean(t *testing.T) {
 	}
 }
 
+func TestGetFlagDuration(t *testing.T) {
+	cmd := &cobra.Command{Use: "test"}
+	ctx := c.GetContextWithCmd(cmd)
+	tests := []struct {
+		name    string
+		args    flag
+		want    time.Duration
+		wantErr bool
+	}{
+		{
+			name: "Valid Duration: 30s",
+			args: flag{
+				name:  "valid-duration",
+				value: "30s",
+				kind:  durationKind,
+			},
+			want:    30 * time.Second,
+			wantErr: false,
+		},
+		{
+			name: "Valid Duration: String flag",
+			args: flag{
+				name:  "valid-string-duration",
+				value: "1m",
+				kind:  reflect.String,
+			},
+			want:    time.Minute,
+			wantErr: false,
+		},
+		{
+			name: "Invalid Duration: String flag",
+			args: flag{
+				name:  "invalid-string-duration",
+				value: "soon",
+				kind:  reflect.String,
+			},
+			want:    0,
+			wantErr: true,
+		},
+		{
+			name: "Invalid Duration: missing",
+			args: flag{
+				name:  "invalid-missing",
+				value: "",
+				kind:  durationKind,
+			},
+			want:    0,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setFlag(cmd, tt.args)
+			got, err := f.GetDuration(ctx, tt.args.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetDuration() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetDuration() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func setFlag(cmd *cobra.Command, flag flag) {
 	switch flag.kind {
 	case reflect.String:
 		cmd.Flags().String(flag.name, "", "")
+	case reflect.Int:
+		cmd.Flags().Int(flag.name, 0, "")
 	case reflect.Int64:
 		cmd.Flags().Int64(flag.name, 0, "")
+	case reflect.Uint32:
+		cmd.Flags().Uint32(flag.name, 0, "")
+	case reflect.Uint64:
+		cmd.Flags().Uint64(flag.name, 0, "")
+	case reflect.Float32:
+		cmd.Flags().Float32(flag.name, 0, "")
 	case reflect.Float64:
 		cmd.Flags().Float64(flag.name, 0, "")
 	case reflect.Bool:
 		cmd.Flags().Bool(flag.name, false, "")
+	case durationKind:
+		cmd.Flags().Duration(flag.name, 0, "")
 	default:
 		panic(fmt.Sprintf("%v unsupported type: %v", flag.name, flag.kind))
 	}
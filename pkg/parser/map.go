@@ -0,0 +1,202 @@
+package parser
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// sepTagName is the tag used to set the separator between map entries
+	sepTagName = "sep"
+	// kvSepTagName is the tag used to set the separator between a map entry's key and value
+	kvSepTagName = "kvsep"
+
+	defaultSep   = ","
+	defaultKVSep = "="
+)
+
+type ErrInvalidMapEntry struct {
+	entry string
+}
+
+func (e ErrInvalidMapEntry) Error() string {
+	return fmt.Sprintf("Invalid map entry: %s", e.entry)
+}
+
+type ErrInvalidMapValue struct {
+	field string
+	key   string
+	err   error
+}
+
+func (e ErrInvalidMapValue) Error() string {
+	return fmt.Sprintf("Invalid value for key %s of field %s: %v", e.key, e.field, e.err)
+}
+
+func (e ErrInvalidMapValue) Unwrap() error {
+	return e.err
+}
+
+// ErrNotMap is returned by a MapParser when the value is a string that should be parsed as a map
+var ErrNotMap = errors.New("value is not a map")
+
+// MapParser is implemented by field parsers that have values which are already maps such as cobra's StringToString flags.
+// Field parsers that don't implement it, or return ErrNotMap, have their string values parsed as maps.
+type MapParser interface {
+	GetStringMap(ctx context.Context, tagValue string) (map[string]string, error)
+}
+
+// setMap sets a map field from the field parser's value for the tag
+func setMap(ctx context.Context, f FieldParser, tag string, field reflect.Value, sf reflect.StructField) error {
+	t := field.Type()
+	if !isValueType(t.Key()) || !isValueType(t.Elem()) {
+		log.Printf("WARNING: Unsupported map type found in struct: %s\n", t)
+		return errUnsupportedType
+	}
+
+	m, err := getStringMap(ctx, f, tag, sf)
+	if err != nil {
+		return err
+	}
+
+	result := reflect.MakeMapWithSize(t, len(m))
+	for k, v := range m {
+		key, err := parseValue(k, t.Key())
+		if err != nil {
+			return ErrInvalidMapValue{field: sf.Name, key: k, err: err}
+		}
+		value, err := parseValue(v, t.Elem())
+		if err != nil {
+			return ErrInvalidMapValue{field: sf.Name, key: k, err: err}
+		}
+		result.SetMapIndex(key, value)
+	}
+
+	field.Set(result)
+	return nil
+}
+
+// getStringMap returns the field parser's map value for the tag, parsing its string value using the field's separators
+// if it isn't already a map
+func getStringMap(ctx context.Context, f FieldParser, tag string, sf reflect.StructField) (map[string]string, error) {
+	if mp, ok := f.(MapParser); ok {
+		m, err := mp.GetStringMap(ctx, tag)
+		if !errors.Is(err, ErrNotMap) {
+			return m, err
+		}
+	}
+
+	value, err := f.GetString(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	sep := sf.Tag.Get(sepTagName)
+	if sep == "" {
+		sep = defaultSep
+	}
+	kvsep := sf.Tag.Get(kvSepTagName)
+	if kvsep == "" {
+		kvsep = defaultKVSep
+	}
+
+	return parseStringMap(value, sep, kvsep)
+}
+
+// parseStringMap parses a JSON object or a list of key value pairs such as team=core,tier=1.
+// A backslash escapes the character after it so separators can be used in keys and values.
+func parseStringMap(s string, sep string, kvsep string) (map[string]string, error) {
+	if strings.HasPrefix(strings.TrimSpace(s), "{") {
+		return parseJSONMap(s)
+	}
+
+	m := map[string]string{}
+	for _, entry := range splitEscaped(s, sep, -1) {
+		if entry == "" {
+			continue
+		}
+
+		kv := splitEscaped(entry, kvsep, 2)
+		if len(kv) != 2 {
+			return nil, ErrInvalidMapEntry{entry}
+		}
+		m[unescape(kv[0])] = unescape(kv[1])
+	}
+
+	return m, nil
+}
+
+// parseJSONMap parses a JSON object whose values are strings, numbers, booleans or null
+func parseJSONMap(s string) (map[string]string, error) {
+	d := json.NewDecoder(strings.NewReader(s))
+	d.UseNumber()
+
+	var raw map[string]interface{}
+	if err := d.Decode(&raw); err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]string, len(raw))
+	for k, v := range raw {
+		switch v := v.(type) {
+		case string:
+			m[k] = v
+		case json.Number:
+			m[k] = v.String()
+		case bool:
+			m[k] = strconv.FormatBool(v)
+		case nil:
+			m[k] = ""
+		default:
+			return nil, ErrInvalidMapEntry{k}
+		}
+	}
+
+	return m, nil
+}
+
+// splitEscaped splits s on separators not escaped with a backslash into at most n parts.
+// Escapes are kept so they can be removed once the parts are split further.
+func splitEscaped(s string, sep string, n int) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if n > 0 && len(parts) == n-1 {
+			break
+		}
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			parts = append(parts, s[start:i])
+			i += len(sep) - 1
+			start = i + 1
+		}
+	}
+
+	return append(parts, s[start:])
+}
+
+// unescape removes the backslashes escaping characters
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
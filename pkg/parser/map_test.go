@@ -0,0 +1,124 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseStringMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		sep     string
+		kvsep   string
+		want    map[string]string
+		wantErr bool
+	}{
+		{name: "Pairs", value: "team=core,tier=1", want: map[string]string{"team": "core", "tier": "1"}},
+		{name: "Empty", value: "", want: map[string]string{}},
+		{name: "Trailing separator", value: "team=core,", want: map[string]string{"team": "core"}},
+		{name: "Empty value", value: "team=", want: map[string]string{"team": ""}},
+		{name: "Equals in value", value: "query=a=b", want: map[string]string{"query": "a=b"}},
+		{name: "Escaped separators", value: `a\,b=c\,d,e\=f=g\\`, want: map[string]string{"a,b": "c,d", "e=f": `g\`}},
+		{name: "Custom separators", value: "team:core;tier:1", sep: ";", kvsep: ":", want: map[string]string{"team": "core", "tier": "1"}},
+		{name: "Multi character separator", value: "a=>1||b=>2", sep: "||", kvsep: "=>", want: map[string]string{"a": "1", "b": "2"}},
+		{name: "JSON object", value: ` {"team": "core", "tier": 1, "on": true, "off": null}`, want: map[string]string{"team": "core", "tier": "1", "on": "true", "off": ""}},
+		{name: "Missing key value separator", value: "team=core,tier", wantErr: true},
+		{name: "Invalid JSON", value: `{"team": }`, wantErr: true},
+		{name: "Nested JSON", value: `{"team": {"name": "core"}}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.sep == "" {
+				tt.sep = defaultSep
+			}
+			if tt.kvsep == "" {
+				tt.kvsep = defaultKVSep
+			}
+
+			got, err := parseStringMap(tt.value, tt.sep, tt.kvsep)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStringMap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseStringMap() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMapParse(t *testing.T) {
+	type Test struct {
+		Labels    map[string]string        `env:"TEST_MAP_LABELS"`
+		Limits    map[string]int           `env:"TEST_MAP_LIMITS"`
+		Features  map[string]bool          `env:"TEST_MAP_FEATURES" sep:";" kvsep:":"`
+		Weights   map[string]float64       `env:"TEST_MAP_WEIGHTS"`
+		Timeouts  map[string]time.Duration `env:"TEST_MAP_TIMEOUTS"`
+		Ports     map[int]uint16           `env:"TEST_MAP_PORTS"`
+		FlagTags  map[string]string        `flag:"test-map-tags"`
+		FlagSizes map[string]int           `flag:"test-map-sizes"`
+		FlagStr   map[string]bool          `flag:"test-map-string" sep:";" kvsep:":"`
+		Unset     map[string]string        `env:"TEST_MAP_UNSET"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringToString("test-map-tags", nil, "")
+	cmd.Flags().StringToInt("test-map-sizes", nil, "")
+	cmd.Flags().String("test-map-string", "", "")
+	if err := cmd.Flags().Parse([]string{
+		"--test-map-tags", "a=1,b=2",
+		"--test-map-sizes", "small=1", "--test-map-sizes", "large=10",
+		"--test-map-string", "beta:true;legacy:false",
+	}); err != nil {
+		t.Fatal(err)
+	}
+	ctx := c.GetContextWithCmd(cmd)
+
+	setEnv(t, env{name: "TEST_MAP_LABELS", value: "team=core,tier=1"})
+	setEnv(t, env{name: "TEST_MAP_LIMITS", value: `{"cpu": 2, "memory": 512}`})
+	setEnv(t, env{name: "TEST_MAP_FEATURES", value: "beta:true;legacy:false"})
+	setEnv(t, env{name: "TEST_MAP_WEIGHTS", value: "a=0.5"})
+	setEnv(t, env{name: "TEST_MAP_TIMEOUTS", value: "read=5s,write=1m"})
+	setEnv(t, env{name: "TEST_MAP_PORTS", value: "80=8080"})
+
+	got := &Test{}
+	err := ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{
+		Labels:    map[string]string{"team": "core", "tier": "1"},
+		Limits:    map[string]int{"cpu": 2, "memory": 512},
+		Features:  map[string]bool{"beta": true, "legacy": false},
+		Weights:   map[string]float64{"a": 0.5},
+		Timeouts:  map[string]time.Duration{"read": 5 * time.Second, "write": time.Minute},
+		Ports:     map[int]uint16{80: 8080},
+		FlagTags:  map[string]string{"a": "1", "b": "2"},
+		FlagSizes: map[string]int{"small": 1, "large": 10},
+		FlagStr:   map[string]bool{"beta": true, "legacy": false},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStruct() = %+v, want %+v", got, want)
+	}
+}
+
+func TestMapParseErrors(t *testing.T) {
+	type Test struct {
+		Limits map[string]int8 `env:"TEST_MAP_ERROR_LIMITS"`
+	}
+
+	setEnv(t, env{name: "TEST_MAP_ERROR_LIMITS", value: "cpu=2,memory=512"})
+	err := ParseStruct(context.Background(), &Test{}, false)
+	want := ErrInvalidMapValue{}
+	if !errors.As(err, &want) || want.key != "memory" || want.field != "Limits" {
+		t.Errorf("ParseStruct() error = %v, want ErrInvalidMapValue for memory", err)
+	}
+}
@@ -3,95 +3,341 @@ package parser
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
 	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skos-ninja/config-loader/pkg/units"
+)
+
+type ErrValueOutOfRange struct {
+	field string
+	kind  reflect.Kind
+	value string
+}
+
+func (e ErrValueOutOfRange) Error() string {
+	return fmt.Sprintf("Value %s is out of range for field %s of type %s", e.value, e.field, e.kind)
+}
+
+// errUnsupportedType is returned when a field's type can't be set by the field parsers
+var errUnsupportedType = errors.New("unsupported type")
+
+// layoutTagName is the tag used to set the layout time.Time fields are parsed with
+const layoutTagName = "layout"
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
 )
 
-// ParseStruct takes a struct ptr and iterates through the fields and applies any field parsers
+// ParseStruct takes a struct ptr and iterates through the fields and applies any field parsers.
+// Field parsers are applied in the order returned by GetPrecedence so later parsers override earlier ones.
+// When failOnParseError is false fields without a value are skipped but invalid values still return an error.
+//
+// Pointer fields are only allocated when a field parser returns a value for them, or for pointers to structs
+// when a value is found for any of the struct's fields, so nil can be used to tell a field was not set.
+//
+// DirectoryParsers read every value from the same update of their volume for the duration of the call.
 func ParseStruct(ctx context.Context, s interface{}, failOnParseError bool) error {
 	rv := reflect.ValueOf(s)
 	if rv.Kind() != reflect.Ptr || rv.IsNil() {
 		return errors.New("struct must be a pointer and not nil")
 	}
 
-	v := rv.Elem()
+	ctx, err := withSnapshots(ctx)
+	if err != nil {
+		return err
+	}
+
+	_, err = parseStruct(ctx, rv.Elem(), failOnParseError)
+	return err
+}
+
+// parseStruct applies the field parsers to the struct's fields and returns if any field was set
+func parseStruct(ctx context.Context, v reflect.Value, failOnParseError bool) (bool, error) {
+	set := false
 
 	for i := 0; i < v.NumField(); i++ {
-		kind := v.Field(i).Kind()
+		field := v.Field(i)
+		sf := v.Type().Field(i)
 
-		if kind == reflect.Struct {
-			inter := v.Field(i).Addr().Interface()
-			err := ParseStruct(ctx, inter, failOnParseError)
+		// Embedded structs are flattened, including those of unexported types as their exported fields can still be set
+		if isNestedStruct(field.Type()) && (field.CanSet() || sf.Anonymous) {
+			ok, err := parseStruct(withStructPrefix(ctx, sf), field, failOnParseError)
 			if err != nil {
-				return err
+				return false, err
 			}
+			set = set || ok
 			continue
 		}
 
-		for k, f := range FieldParsers {
-			tag := v.Type().Field(i).Tag.Get(k)
+		if !field.CanSet() {
+			continue
+		}
+
+		if field.Kind() == reflect.Ptr && isNestedStruct(field.Type().Elem()) && !isDecodable(field.Type()) {
+			ok, err := parseStructPtr(withStructPrefix(ctx, sf), field, failOnParseError)
+			if err != nil {
+				return false, err
+			}
+			set = set || ok
+			continue
+		}
+
+		if isStructSlice(field.Type()) {
+			ok, err := parseStructSlice(ctx, field, sf, failOnParseError)
+			if err != nil {
+				return false, err
+			}
+			set = set || ok
+			continue
+		}
+
+		for _, k := range GetPrecedence(ctx) {
+			f, ok := GetFieldParser(ctx, k)
+			if !ok {
+				continue
+			}
 
 			// Skip if tag is not defined or ignored
-			if tag == "" || tag == "-" {
+			tag, ok := fieldName(ctx, k, sf)
+			if !ok {
 				continue
 			}
 
-			switch kind {
-			case reflect.Slice:
-				switch v.Field(i).Type().Elem().Kind() {
-				case reflect.String:
-					s, err := f.GetStringSlice(ctx, tag)
-					if err != nil {
-						if failOnParseError {
-							return err
-						}
-						continue
-					}
-					v.Field(i).Set(reflect.ValueOf(s))
-				default:
-					log.Printf("WARNING: Unsupported slice type found in struct: %s\n", v.Field(i).Type().Elem().Kind())
-				}
-			case reflect.String:
-				value, err := f.GetString(ctx, tag)
-				if err != nil {
-					if failOnParseError {
-						return err
-					}
-					continue
-				}
-				v.Field(i).SetString(value)
-			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-				value, err := f.GetInt(ctx, tag)
-				if err != nil {
-					if failOnParseError {
-						return err
-					}
-					continue
-				}
-				v.Field(i).SetInt(value)
-			case reflect.Float64, reflect.Float32:
-				value, err := f.GetFloat(ctx, tag)
-				if err != nil {
-					if failOnParseError {
-						return err
-					}
-					continue
-				}
-				v.Field(i).SetFloat(value)
-			case reflect.Bool:
-				value, err := f.GetBoolean(ctx, tag)
-				if err != nil {
-					if failOnParseError {
-						return err
-					}
-					continue
+			err := setField(ctx, f, tag, field, sf)
+			if errors.Is(err, errUnsupportedType) {
+				continue
+			}
+			if err != nil {
+				if failOnParseError || !isNotFound(err) {
+					return false, err
 				}
-				v.Field(i).SetBool(value)
-			default:
-				log.Printf("WARNING: Unsupported type found in struct: %s\n", v.Type().Field(i).Name)
+				continue
 			}
+			set = true
+		}
+	}
+
+	return set, nil
+}
+
+// parseStructPtr parses a pointer to a struct allocating it only if any of its fields are set
+func parseStructPtr(ctx context.Context, field reflect.Value, failOnParseError bool) (bool, error) {
+	if !field.IsNil() {
+		return parseStruct(ctx, field.Elem(), failOnParseError)
+	}
+
+	value := reflect.New(field.Type().Elem())
+	set, err := parseStruct(ctx, value.Elem(), failOnParseError)
+	if err != nil || !set {
+		return false, err
+	}
+
+	field.Set(value)
+	return true, nil
+}
+
+// isNestedStruct returns if the type is a struct whose fields should be parsed rather than being a value itself
+func isNestedStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t != timeType && !isDecodable(t)
+}
+
+// setField sets the field to the value the field parser returns for the tag
+func setField(ctx context.Context, f FieldParser, tag string, field reflect.Value, sf reflect.StructField) error {
+	// Registered decoders may be for pointer types such as *regexp.Regexp so are checked before pointers are followed
+	if _, ok := getDecoder(field.Type()); ok {
+		return setDecoded(ctx, f, tag, field, sf)
+	}
+
+	// Pointers are set to a new value so they stay nil when there is no value
+	if field.Kind() == reflect.Ptr {
+		value := reflect.New(field.Type().Elem())
+		err := setField(ctx, f, tag, value.Elem(), sf)
+		if err != nil {
+			return err
+		}
+		field.Set(value)
+		return nil
+	}
+
+	switch field.Type() {
+	case durationType:
+		value, err := getDuration(ctx, f, tag)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(value))
+		return nil
+	case timeType:
+		value, err := f.GetString(ctx, tag)
+		if err != nil {
+			return err
+		}
+		layout := sf.Tag.Get(layoutTagName)
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		t, err := time.Parse(layout, value)
+		if err != nil {
+			return err
 		}
+		field.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if isDecodable(field.Type()) {
+		return setDecoded(ctx, f, tag, field, sf)
+	}
+
+	switch field.Kind() {
+	case reflect.Map:
+		return setMap(ctx, f, tag, field, sf)
+	case reflect.Slice:
+		return setSlice(ctx, f, tag, field, sf)
+	case reflect.String:
+		value, err := f.GetString(ctx, tag)
+		if err != nil {
+			return err
+		}
+		field.SetString(value)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		value, err := getInt(ctx, f, tag, sf)
+		if err != nil {
+			return rangeError(err, field.Kind(), sf)
+		}
+		if field.OverflowInt(value) {
+			return ErrValueOutOfRange{field: sf.Name, kind: field.Kind(), value: strconv.FormatInt(value, 10)}
+		}
+		field.SetInt(value)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		value, err := getUint(ctx, f, tag, field.Kind(), sf)
+		if err != nil {
+			return rangeError(err, field.Kind(), sf)
+		}
+		if field.OverflowUint(value) {
+			return ErrValueOutOfRange{field: sf.Name, kind: field.Kind(), value: strconv.FormatUint(value, 10)}
+		}
+		field.SetUint(value)
+	case reflect.Float64, reflect.Float32:
+		value, err := f.GetFloat(ctx, tag)
+		if err != nil {
+			return err
+		}
+		field.SetFloat(value)
+	case reflect.Bool:
+		value, err := f.GetBoolean(ctx, tag)
+		if err != nil {
+			return err
+		}
+		field.SetBool(value)
+	default:
+		log.Printf("WARNING: Unsupported type found in struct: %s\n", sf.Name)
+		return errUnsupportedType
 	}
 
 	return nil
 }
+
+// getDuration returns the field parser's duration value for the tag
+func getDuration(ctx context.Context, f FieldParser, tag string) (time.Duration, error) {
+	if dp, ok := f.(DurationParser); ok {
+		return dp.GetDuration(ctx, tag)
+	}
+
+	value, err := f.GetString(ctx, tag)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.ParseDuration(value)
+}
+
+// getInt returns the field parser's integer value for the tag, parsed as the field's unit if it has a unit tag
+func getInt(ctx context.Context, f FieldParser, tag string, sf reflect.StructField) (int64, error) {
+	unit := sf.Tag.Get(units.TagName)
+	if unit == "" {
+		return f.GetInt(ctx, tag)
+	}
+
+	value, err := f.GetString(ctx, tag)
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := units.Parse(unit, value)
+	if err != nil {
+		return 0, ErrInvalidValue{field: sf.Name, value: value, err: err}
+	}
+
+	return n, nil
+}
+
+// getUint returns the field parser's unsigned integer value for the tag, parsed as the field's unit if it has a unit tag
+func getUint(ctx context.Context, f FieldParser, tag string, kind reflect.Kind, sf reflect.StructField) (uint64, error) {
+	if sf.Tag.Get(units.TagName) == "" {
+		if up, ok := f.(UintParser); ok {
+			return up.GetUint(ctx, tag)
+		}
+
+		value, err := f.GetString(ctx, tag)
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseUint(value, 10, 64)
+	}
+
+	value, err := getInt(ctx, f, tag, sf)
+	if err != nil {
+		return 0, err
+	}
+	if value < 0 {
+		return 0, ErrValueOutOfRange{field: sf.Name, kind: kind, value: strconv.FormatInt(value, 10)}
+	}
+
+	return uint64(value), nil
+}
+
+// rangeError returns an ErrValueOutOfRange for errors from parsing an integer that doesn't fit in 64 bits
+// or a negative value for an unsigned field
+func rangeError(err error, kind reflect.Kind, sf reflect.StructField) error {
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		return err
+	}
+
+	if errors.Is(numErr.Err, strconv.ErrRange) || (isUnsigned(kind) && isNegative(numErr.Num)) {
+		return ErrValueOutOfRange{field: sf.Name, kind: kind, value: numErr.Num}
+	}
+
+	return err
+}
+
+// isUnsigned returns if the kind is an unsigned integer
+func isUnsigned(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+// isNegative returns if the value is a negative integer
+func isNegative(value string) bool {
+	_, err := strconv.ParseInt(value, 10, 64)
+	return strings.HasPrefix(value, "-") && (err == nil || errors.Is(err, strconv.ErrRange))
+}
+
+// isNotFound returns if the error is from a field parser not having a value for a tag
+func isNotFound(err error) bool {
+	return errors.As(err, &ErrEnvVariableNotFound{}) ||
+		errors.As(err, &ErrFlagNotFound{}) ||
+		errors.As(err, &ErrFileNotFound{}) ||
+		errors.Is(err, ErrNotUsingCobraCtx) ||
+		errors.Is(err, ErrFlagsNotFound)
+}
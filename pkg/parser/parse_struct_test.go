@@ -2,11 +2,15 @@ package parser
 
 import (
 	"context"
+	"errors"
+	"os"
 	"reflect"
 	"strconv"
 	"testing"
+	"time"
 
 	c "github.com/skos-ninja/config-loader/pkg/context"
+	"github.com/skos-ninja/config-loader/pkg/units"
 
 	"github.com/spf13/cobra"
 )
@@ -158,3 +162,455 @@ func TestSlice(t *testing.T) {
 		t.Errorf("parseStruct() = %v, want %v", original, expected)
 	}
 }
+
+func TestPrecedence(t *testing.T) {
+	type Test struct {
+		Str string `env:"test-precedence" flag:"test-precedence"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	setEnv(t, env{name: "test-precedence", value: "env-value"})
+	setFlag(cmd, flag{name: "test-precedence", value: "flag-value", kind: reflect.String})
+	ctx := c.GetContextWithCmd(cmd)
+
+	tests := []struct {
+		name       string
+		precedence []string
+		want       string
+	}{
+		{
+			name:       "Default precedence",
+			precedence: nil,
+			want:       "flag-value",
+		},
+		{
+			name:       "Env over flag",
+			precedence: []string{flagTagName, envTagName},
+			want:       "env-value",
+		},
+		{
+			name:       "Env only",
+			precedence: []string{envTagName},
+			want:       "env-value",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := ctx
+			if tt.precedence != nil {
+				ctx = WithPrecedence(ctx, tt.precedence)
+			}
+
+			// Map iteration order is random so repeat to catch any non determinism
+			for i := 0; i < 100; i++ {
+				original := &Test{}
+				err := ParseStruct(ctx, original, true)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if original.Str != tt.want {
+					t.Fatalf("parseStruct() iteration %d = %v, want %v", i, original.Str, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	type Test struct {
+		Inter int `env:"TEST_PARSE_ERRORS_INT"`
+	}
+
+	t.Run("Missing values are skipped", func(t *testing.T) {
+		err := ParseStruct(context.Background(), &Test{}, false)
+		if err != nil {
+			t.Errorf("parseStruct() error = %v, want nil", err)
+		}
+	})
+
+	t.Run("Invalid values return an error", func(t *testing.T) {
+		setEnv(t, env{name: "TEST_PARSE_ERRORS_INT", value: "not-an-int"})
+		defer os.Unsetenv("TEST_PARSE_ERRORS_INT")
+
+		err := ParseStruct(context.Background(), &Test{}, false)
+		if err == nil {
+			t.Error("parseStruct() expected error")
+		}
+	})
+}
+
+func TestTimeParse(t *testing.T) {
+	type Test struct {
+		EnvDuration  time.Duration `env:"TEST_TIME_DURATION"`
+		FlagDuration time.Duration `flag:"test-time-duration"`
+		EnvTime      time.Time     `env:"TEST_TIME_TIME"`
+		FlagTime     time.Time     `flag:"test-time-time" layout:"2006-01-02"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	setEnv(t, env{name: "TEST_TIME_DURATION", value: "30s"})
+	setEnv(t, env{name: "TEST_TIME_TIME", value: "2021-01-02T03:04:05Z"})
+	setFlag(cmd, flag{name: "test-time-duration", value: "1m30s", kind: durationKind})
+	setFlag(cmd, flag{name: "test-time-time", value: "2021-01-02", kind: reflect.String})
+	ctx := c.GetContextWithCmd(cmd)
+
+	original := &Test{}
+	expected := &Test{
+		EnvDuration:  30 * time.Second,
+		FlagDuration: 90 * time.Second,
+		EnvTime:      time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		FlagTime:     time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+	}
+	err := ParseStruct(ctx, original, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(original, expected) {
+		t.Errorf("parseStruct() = %v, want %v", *original, *expected)
+	}
+}
+
+func TestTimeParseErrors(t *testing.T) {
+	type Duration struct {
+		Duration time.Duration `env:"TEST_TIME_INVALID_DURATION"`
+	}
+	type Time struct {
+		Time time.Time `env:"TEST_TIME_INVALID_TIME" layout:"2006-01-02"`
+	}
+
+	setEnv(t, env{name: "TEST_TIME_INVALID_DURATION", value: "30"})
+	setEnv(t, env{name: "TEST_TIME_INVALID_TIME", value: "2021-01-02T03:04:05Z"})
+
+	if err := ParseStruct(context.Background(), &Duration{}, false); err == nil {
+		t.Error("parseStruct() expected duration error")
+	}
+	if err := ParseStruct(context.Background(), &Time{}, false); err == nil {
+		t.Error("parseStruct() expected time error")
+	}
+}
+
+func TestIntegerParse(t *testing.T) {
+	type Test struct {
+		Int8    int8    `env:"TEST_INTEGER_INT8"`
+		Int16   int16   `env:"TEST_INTEGER_INT16"`
+		Int32   int32   `env:"TEST_INTEGER_INT32"`
+		Uint    uint    `env:"TEST_INTEGER_UINT"`
+		Uint8   uint8   `env:"TEST_INTEGER_UINT8"`
+		Uint16  uint16  `env:"TEST_INTEGER_UINT16"`
+		Uint32  uint32  `env:"TEST_INTEGER_UINT32"`
+		Uint64  uint64  `flag:"test-integer-uint64"`
+		Uintptr uintptr `env:"TEST_INTEGER_UINTPTR"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	setEnv(t, env{name: "TEST_INTEGER_INT8", value: "-128"})
+	setEnv(t, env{name: "TEST_INTEGER_INT16", value: "32767"})
+	setEnv(t, env{name: "TEST_INTEGER_INT32", value: "-2147483648"})
+	setEnv(t, env{name: "TEST_INTEGER_UINT", value: "1"})
+	setEnv(t, env{name: "TEST_INTEGER_UINT8", value: "255"})
+	setEnv(t, env{name: "TEST_INTEGER_UINT16", value: "65535"})
+	setEnv(t, env{name: "TEST_INTEGER_UINT32", value: "4294967295"})
+	setEnv(t, env{name: "TEST_INTEGER_UINTPTR", value: "4096"})
+	setFlag(cmd, flag{name: "test-integer-uint64", value: "18446744073709551615", kind: reflect.Uint64})
+	ctx := c.GetContextWithCmd(cmd)
+
+	original := &Test{}
+	expected := &Test{
+		Int8:    -128,
+		Int16:   32767,
+		Int32:   -2147483648,
+		Uint:    1,
+		Uint8:   255,
+		Uint16:  65535,
+		Uint32:  4294967295,
+		Uint64:  18446744073709551615,
+		Uintptr: 4096,
+	}
+	err := ParseStruct(ctx, original, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *original != *expected {
+		t.Errorf("parseStruct() = %v, want %v", *original, *expected)
+	}
+}
+
+func TestIntegerOutOfRange(t *testing.T) {
+	type Int8 struct {
+		Small int8 `env:"TEST_RANGE_INT8"`
+	}
+	type Uint8 struct {
+		Small uint8 `env:"TEST_RANGE_UINT8"`
+	}
+	type Uint struct {
+		Unsigned uint `env:"TEST_RANGE_UINT"`
+	}
+	type Int64 struct {
+		Large int64 `env:"TEST_RANGE_INT64"`
+	}
+	type Uint64 struct {
+		Large uint64 `env:"TEST_RANGE_UINT64"`
+	}
+
+	setEnv(t, env{name: "TEST_RANGE_INT8", value: "128"})
+	setEnv(t, env{name: "TEST_RANGE_UINT8", value: "256"})
+	setEnv(t, env{name: "TEST_RANGE_UINT", value: "-1"})
+	setEnv(t, env{name: "TEST_RANGE_INT64", value: "99999999999999999999"})
+	setEnv(t, env{name: "TEST_RANGE_UINT64", value: "99999999999999999999"})
+
+	err := ParseStruct(context.Background(), &Int8{}, false)
+	expectErr := ErrValueOutOfRange{field: "Small", kind: reflect.Int8, value: "128"}
+	if err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+
+	err = ParseStruct(context.Background(), &Uint8{}, false)
+	expectErr = ErrValueOutOfRange{field: "Small", kind: reflect.Uint8, value: "256"}
+	if err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+
+	err = ParseStruct(context.Background(), &Uint{}, false)
+	expectErr = ErrValueOutOfRange{field: "Unsigned", kind: reflect.Uint, value: "-1"}
+	if err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+
+	err = ParseStruct(context.Background(), &Int64{}, false)
+	expectErr = ErrValueOutOfRange{field: "Large", kind: reflect.Int64, value: "99999999999999999999"}
+	if err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+
+	err = ParseStruct(context.Background(), &Uint64{}, false)
+	expectErr = ErrValueOutOfRange{field: "Large", kind: reflect.Uint64, value: "99999999999999999999"}
+	if err != expectErr {
+		t.Errorf("expected() = %v, want %v", err, expectErr)
+	}
+}
+
+func TestPointerParse(t *testing.T) {
+	type Nested struct {
+		Field string `env:"TEST_POINTER_NESTED"`
+	}
+	type Test struct {
+		Inter    *int           `env:"TEST_POINTER_INT"`
+		Boolean  *bool          `flag:"test-pointer-bool"`
+		Str      *string        `env:"TEST_POINTER_STRING"`
+		Duration *time.Duration `env:"TEST_POINTER_DURATION"`
+		Unset    *int           `env:"TEST_POINTER_UNSET"`
+		Nested   *Nested
+		Existing *Nested
+	}
+	type Unset struct {
+		Field string `env:"TEST_POINTER_UNSET"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	setEnv(t, env{name: "TEST_POINTER_INT", value: "0"})
+	setEnv(t, env{name: "TEST_POINTER_STRING", value: "", forceEmpty: true})
+	setEnv(t, env{name: "TEST_POINTER_DURATION", value: "30s"})
+	setEnv(t, env{name: "TEST_POINTER_NESTED", value: "nested"})
+	setFlag(cmd, flag{name: "test-pointer-bool", value: "false", kind: reflect.Bool})
+	ctx := c.GetContextWithCmd(cmd)
+
+	existing := &Nested{Field: "existing"}
+	original := &Test{Existing: existing}
+	err := ParseStruct(ctx, original, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if original.Inter == nil || *original.Inter != 0 {
+		t.Errorf("parseStruct() Inter = %v, want 0", original.Inter)
+	}
+	if original.Boolean == nil || *original.Boolean {
+		t.Errorf("parseStruct() Boolean = %v, want false", original.Boolean)
+	}
+	if original.Str == nil || *original.Str != "" {
+		t.Errorf("parseStruct() Str = %v, want empty string", original.Str)
+	}
+	if original.Duration == nil || *original.Duration != 30*time.Second {
+		t.Errorf("parseStruct() Duration = %v, want 30s", original.Duration)
+	}
+	if original.Unset != nil {
+		t.Errorf("parseStruct() Unset = %v, want nil", *original.Unset)
+	}
+	if original.Nested == nil || original.Nested.Field != "nested" {
+		t.Errorf("parseStruct() Nested = %v, want nested", original.Nested)
+	}
+	if original.Existing != existing || existing.Field != "nested" {
+		t.Errorf("parseStruct() Existing = %v, want existing pointer set to nested", original.Existing)
+	}
+
+	unset := &struct{ Unset *Unset }{}
+	err = ParseStruct(ctx, unset, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unset.Unset != nil {
+		t.Errorf("parseStruct() Unset = %v, want nil", unset.Unset)
+	}
+}
+
+func TestUnitParse(t *testing.T) {
+	type Test struct {
+		Cache    int64           `env:"TEST_UNIT_CACHE" unit:"bytes"`
+		Buffer   *uint32         `env:"TEST_UNIT_BUFFER" unit:"bytes"`
+		Workers  int             `flag:"test-unit-workers" unit:"quantity"`
+		Size     units.ByteSize  `env:"TEST_UNIT_SIZE"`
+		Requests units.Quantity  `flag:"test-unit-requests"`
+		Limit    *units.ByteSize `env:"TEST_UNIT_UNSET"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	setFlag(cmd, flag{name: "test-unit-workers", value: "2.5k", kind: reflect.String})
+	requests := units.Quantity(0)
+	cmd.Flags().Var(&requests, "test-unit-requests", "")
+	if err := cmd.Flags().Set("test-unit-requests", "1_000_000"); err != nil {
+		t.Fatal(err)
+	}
+	ctx := c.GetContextWithCmd(cmd)
+
+	setEnv(t, env{name: "TEST_UNIT_CACHE", value: "512MiB"})
+	setEnv(t, env{name: "TEST_UNIT_BUFFER", value: "64KiB"})
+	setEnv(t, env{name: "TEST_UNIT_SIZE", value: "1.5GiB"})
+
+	got := &Test{}
+	err := ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer := uint32(64 << 10)
+	want := &Test{Cache: 512 << 20, Buffer: &buffer, Workers: 2500, Size: 3 << 29, Requests: 1000000}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseStruct() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUnitParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		s     interface{}
+		want  error
+	}{
+		{
+			name:  "Invalid size",
+			value: "10XB",
+			s: &struct {
+				V int64 `env:"TEST_UNIT_ERROR" unit:"bytes"`
+			}{},
+			want: ErrInvalidValue{},
+		},
+		{
+			name:  "Unknown unit",
+			value: "10",
+			s: &struct {
+				V int64 `env:"TEST_UNIT_ERROR" unit:"seconds"`
+			}{},
+			want: ErrInvalidValue{},
+		},
+		{
+			name:  "Out of range",
+			value: "1GiB",
+			s: &struct {
+				V int16 `env:"TEST_UNIT_ERROR" unit:"bytes"`
+			}{},
+			want: ErrValueOutOfRange{},
+		},
+		{
+			name:  "Negative unsigned",
+			value: "-1k",
+			s: &struct {
+				V uint `env:"TEST_UNIT_ERROR" unit:"quantity"`
+			}{},
+			want: ErrValueOutOfRange{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, env{name: "TEST_UNIT_ERROR", value: tt.value})
+			err := ParseStruct(context.Background(), tt.s, false)
+			target := reflect.New(reflect.TypeOf(tt.want)).Interface()
+			if !errors.As(err, target) {
+				t.Errorf("ParseStruct() error = %v, want %T", err, tt.want)
+			}
+		})
+	}
+}
+
+// stringParser is a field parser implementing only the methods FieldParser requires
+type stringParser map[string]string
+
+func (p stringParser) GetString(ctx context.Context, key string) (string, error) {
+	return p[key], nil
+}
+
+func (p stringParser) GetInt(ctx context.Context, key string) (int64, error) {
+	return strconv.ParseInt(p[key], 10, 64)
+}
+
+func (p stringParser) GetFloat(ctx context.Context, key string) (float64, error) {
+	return strconv.ParseFloat(p[key], 64)
+}
+
+func (p stringParser) GetBoolean(ctx context.Context, key string) (bool, error) {
+	return strconv.ParseBool(p[key])
+}
+
+func (p stringParser) GetStringSlice(ctx context.Context, key string) ([]string, error) {
+	return []string{p[key]}, nil
+}
+
+func TestCustomParser(t *testing.T) {
+	type Test struct {
+		Timeout time.Duration `custom:"timeout"`
+		Size    uint32        `custom:"size"`
+	}
+
+	FieldParsers["custom"] = stringParser{"timeout": "30s", "size": "4096"}
+	defer delete(FieldParsers, "custom")
+	ctx := WithPrecedence(context.Background(), []string{"custom"})
+
+	got := &Test{}
+	err := ParseStruct(ctx, got, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Test{Timeout: 30 * time.Second, Size: 4096}); *got != want {
+		t.Errorf("ParseStruct() = %v, want %v", *got, want)
+	}
+}
+
+func TestCustomParserDefaultPrecedence(t *testing.T) {
+	type Test struct {
+		Secret string `vault:"secret"`
+		Token  string `custom:"token" env:"TEST_CUSTOM_PRECEDENCE"`
+	}
+
+	FieldParsers["vault"] = stringParser{"secret": "from vault"}
+	FieldParsers["custom"] = stringParser{"token": "from custom"}
+	defer delete(FieldParsers, "vault")
+	defer delete(FieldParsers, "custom")
+	setEnv(t, env{name: "TEST_CUSTOM_PRECEDENCE", value: "from env"})
+
+	want := []string{fileTagName, envTagName, flagTagName, "custom", "vault"}
+	if got := GetPrecedence(context.Background()); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetPrecedence() = %v, want %v", got, want)
+	}
+
+	// Registered parsers are applied after the default parsers
+	got := &Test{}
+	err := ParseStruct(context.Background(), got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Test{Secret: "from vault", Token: "from custom"}); *got != want {
+		t.Errorf("ParseStruct() = %v, want %v", *got, want)
+	}
+}
@@ -2,6 +2,7 @@ package parser
 
 import (
 	"context"
+	"time"
 )
 
 // FieldParser is an interface for fetching a field value given a tag value
@@ -13,8 +14,39 @@ type FieldParser interface {
 	GetStringSlice(ctx context.Context, tagValue string) ([]string, error)
 }
 
-// FieldParsers is a collection of tags to parsers for the parser to use
+// UintParser is implemented by field parsers that have values which are already unsigned integers such as cobra's Uint flags.
+// Field parsers that don't implement it have their string values parsed with strconv.ParseUint.
+type UintParser interface {
+	GetUint(ctx context.Context, tagValue string) (uint64, error)
+}
+
+// DurationParser is implemented by field parsers that have values which are already durations such as cobra's Duration flags.
+// Field parsers that don't implement it have their string values parsed with time.ParseDuration.
+type DurationParser interface {
+	GetDuration(ctx context.Context, tagValue string) (time.Duration, error)
+}
+
+// FieldParsers is a collection of tags to parsers for the parser to use.
+// Parsers set on the context with WithFieldParser take precedence over these.
 var FieldParsers = map[string]FieldParser{
+	fileTagName: FileParser{},
 	envTagName:  EnvironmentParser{},
 	flagTagName: FlagParser{},
 }
+
+type fieldParserKey string
+
+// WithFieldParser returns a context that uses the field parser for the tag in place of any in FieldParsers
+func WithFieldParser(ctx context.Context, tag string, f FieldParser) context.Context {
+	return context.WithValue(ctx, fieldParserKey(tag), f)
+}
+
+// GetFieldParser returns the field parser for the tag set on the context or in FieldParsers
+func GetFieldParser(ctx context.Context, tag string) (FieldParser, bool) {
+	if f, ok := ctx.Value(fieldParserKey(tag)).(FieldParser); ok {
+		return f, true
+	}
+
+	f, ok := FieldParsers[tag]
+	return f, ok
+}
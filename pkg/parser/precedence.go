@@ -0,0 +1,56 @@
+package parser
+
+import (
+	"context"
+	"sort"
+)
+
+type contextKey string
+
+var precedenceKey contextKey = "precedence"
+
+// DefaultPrecedence is the order field parsers are applied in when none is set on the context.
+// Parsers later in the list take precedence over earlier ones as their values are applied last.
+var DefaultPrecedence = []string{fileTagName, envTagName, flagTagName}
+
+// WithPrecedence returns a context that applies the field parsers for the given tags in order.
+// Only the tags listed are consulted, with later tags overriding values set by earlier ones.
+func WithPrecedence(ctx context.Context, tags []string) context.Context {
+	return context.WithValue(ctx, precedenceKey, tags)
+}
+
+// GetPrecedence returns the order of tags set on the context. If none is set DefaultPrecedence is returned followed by
+// the tags of any other parsers in FieldParsers in sorted order so registered parsers are always applied.
+func GetPrecedence(ctx context.Context) []string {
+	v := ctx.Value(precedenceKey)
+	if v != nil {
+		return v.([]string)
+	}
+
+	precedence := append([]string{}, DefaultPrecedence...)
+	var registered []string
+	for tag := range FieldParsers {
+		if !contains(precedence, tag) {
+			registered = append(registered, tag)
+		}
+	}
+	sort.Strings(registered)
+
+	return append(precedence, registered...)
+}
+
+// hasPrecedence returns if the tag is in the order of tags set on the context
+func hasPrecedence(ctx context.Context, tag string) bool {
+	return contains(GetPrecedence(ctx), tag)
+}
+
+// contains returns if the tag is in the tags
+func contains(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"context"
+	"reflect"
+	"strings"
+)
+
+// prefixTagName is the tag used to prefix the env and flag names of a nested struct's fields
+const prefixTagName = "prefix"
+
+var tagPrefixKey contextKey = "tagPrefix"
+
+// tagPrefix is the prefix for the names of a tag.
+// Names set by tags use the tag prefix while derived names also include the names of the structs they are nested in.
+type tagPrefix struct {
+	tagged  string
+	derived string
+}
+
+// updateTagPrefix returns a context where the prefix for the tag has been updated by the func
+func updateTagPrefix(ctx context.Context, tag string, update func(p tagPrefix) tagPrefix) context.Context {
+	prefixes := map[string]tagPrefix{}
+	if v, ok := ctx.Value(tagPrefixKey).(map[string]tagPrefix); ok {
+		for k, p := range v {
+			prefixes[k] = p
+		}
+	}
+	prefixes[tag] = update(prefixes[tag])
+
+	return context.WithValue(ctx, tagPrefixKey, prefixes)
+}
+
+// withTagPrefix returns a context where names for the tag are prefixed with the prefix after any existing prefix
+func withTagPrefix(ctx context.Context, tag string, prefix string) context.Context {
+	return updateTagPrefix(ctx, tag, func(p tagPrefix) tagPrefix {
+		return tagPrefix{tagged: p.tagged + prefix, derived: p.derived + prefix}
+	})
+}
+
+// withDerivedPrefix returns a context where only derived names for the tag are prefixed with the prefix after any existing prefix
+func withDerivedPrefix(ctx context.Context, tag string, prefix string) context.Context {
+	return updateTagPrefix(ctx, tag, func(p tagPrefix) tagPrefix {
+		return tagPrefix{tagged: p.tagged, derived: p.derived + prefix}
+	})
+}
+
+// setTagPrefix returns a context where all names for the tag are prefixed with the prefix replacing any existing prefix
+func setTagPrefix(ctx context.Context, tag string, prefix string) context.Context {
+	return updateTagPrefix(ctx, tag, func(p tagPrefix) tagPrefix {
+		return tagPrefix{tagged: prefix, derived: prefix}
+	})
+}
+
+// getTagPrefix returns the prefix for names of the tag
+func getTagPrefix(ctx context.Context, tag string) tagPrefix {
+	if v, ok := ctx.Value(tagPrefixKey).(map[string]tagPrefix); ok {
+		return v[tag]
+	}
+
+	return tagPrefix{}
+}
+
+// withStructPrefix returns a context where the env and flag names of a nested struct field's fields are prefixed by its prefix tag.
+// The prefix is used as is for env names and converted to lower case with dashes for flag names so PRIMARY_DB_ is primary-db-.
+//
+// Without a prefix tag derived names are prefixed with the field's name unless the struct is embedded.
+func withStructPrefix(ctx context.Context, sf reflect.StructField) context.Context {
+	prefix := sf.Tag.Get(prefixTagName)
+	if prefix == "" {
+		if sf.Anonymous || !hasAutoNames(ctx) {
+			return ctx
+		}
+
+		ctx = withDerivedPrefix(ctx, envTagName, envName(sf.Name)+"_")
+		return withDerivedPrefix(ctx, flagTagName, flagName(sf.Name)+"-")
+	}
+
+	ctx = withTagPrefix(ctx, envTagName, prefix)
+	return withTagPrefix(ctx, flagTagName, strings.ReplaceAll(strings.ToLower(prefix), "_", "-"))
+}
@@ -0,0 +1,66 @@
+package parser
+
+import (
+	"reflect"
+	"testing"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+
+	"github.com/spf13/cobra"
+)
+
+type testDatabase struct {
+	Host string `env:"HOST" flag:"host"`
+	Port int    `env:"PORT" flag:"port"`
+}
+
+type TestEmbedded struct {
+	Name string `env:"NAME"`
+}
+
+type testUnexported struct {
+	Region string `env:"TEST_PREFIX_REGION"`
+}
+
+func TestPrefixParse(t *testing.T) {
+	type Pool struct {
+		Database testDatabase `prefix:"POOL_"`
+	}
+	type Test struct {
+		TestEmbedded `prefix:"TEST_PREFIX_"`
+		testUnexported
+		Primary testDatabase  `prefix:"TEST_PREFIX_PRIMARY_DB_"`
+		Replica *testDatabase `prefix:"TEST_PREFIX_REPLICA_DB_"`
+		Pool    Pool          `prefix:"TEST_PREFIX_"`
+		Unset   *testDatabase `prefix:"TEST_PREFIX_UNSET_"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	setFlag(cmd, flag{name: "test-prefix-primary-db-host", value: "primary-flag", kind: reflect.String})
+	setFlag(cmd, flag{name: "test-prefix-replica-db-port", value: "5433", kind: reflect.Int64})
+	ctx := c.GetContextWithCmd(cmd)
+
+	setEnv(t, env{name: "TEST_PREFIX_NAME", value: "embedded"})
+	setEnv(t, env{name: "TEST_PREFIX_REGION", value: "eu"})
+	setEnv(t, env{name: "TEST_PREFIX_PRIMARY_DB_HOST", value: "primary"})
+	setEnv(t, env{name: "TEST_PREFIX_PRIMARY_DB_PORT", value: "5432"})
+	setEnv(t, env{name: "TEST_PREFIX_REPLICA_DB_HOST", value: "replica"})
+	setEnv(t, env{name: "TEST_PREFIX_POOL_HOST", value: "pool"})
+
+	got := &Test{}
+	err := ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{
+		TestEmbedded:   TestEmbedded{Name: "embedded"},
+		testUnexported: testUnexported{Region: "eu"},
+		Primary:        testDatabase{Host: "primary-flag", Port: 5432},
+		Replica:        &testDatabase{Host: "replica", Port: 5433},
+		Pool:           Pool{Database: testDatabase{Host: "pool"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStruct() = %+v, want %+v", got, want)
+	}
+}
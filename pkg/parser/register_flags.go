@@ -0,0 +1,378 @@
+package parser
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strconv"
+	"time"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+	"github.com/skos-ninja/config-loader/pkg/units"
+
+	"github.com/spf13/pflag"
+)
+
+// Tags used to configure the flags created by RegisterFlags
+const (
+	usageTagName      = "usage"
+	shortTagName      = "short"
+	hiddenTagName     = "hidden"
+	persistentTagName = "persistent"
+)
+
+type ErrDuplicateFlag struct {
+	flag string
+}
+
+func (e ErrDuplicateFlag) Error() string {
+	return fmt.Sprintf("Flag already registered: %s", e.flag)
+}
+
+type ErrInvalidTag struct {
+	field string
+	tag   string
+	value string
+}
+
+func (e ErrInvalidTag) Error() string {
+	return fmt.Sprintf("Invalid %s tag on field %s: %s", e.tag, e.field, e.value)
+}
+
+var (
+	ipType    = reflect.TypeOf(net.IP{})
+	ipNetType = reflect.TypeOf(net.IPNet{})
+)
+
+// RegisterFlags creates a flag on the cobra command in the context for each field with a flag tag,
+// or every field when WithAutoNames is used, with the field's current value as the default.
+//
+// Flags are typed to match their field and are configured by the usage, short, hidden and persistent tags.
+// An ErrDuplicateFlag error is returned if a flag name or shorthand is already registered.
+func RegisterFlags(ctx context.Context, s interface{}) error {
+	cmd := c.GetCmdFromContext(ctx)
+	if cmd == nil {
+		return ErrNotUsingCobraCtx
+	}
+
+	rv := reflect.ValueOf(s)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.New("struct must be a pointer and not nil")
+	}
+
+	return registerStructFlags(ctx, rv.Elem())
+}
+
+// registerStructFlags registers the flags for the struct's fields following the same nesting rules as parseStruct
+func registerStructFlags(ctx context.Context, v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Field(i)
+		sf := v.Type().Field(i)
+
+		if isNestedStruct(field.Type()) && (field.CanSet() || sf.Anonymous) {
+			if err := registerStructFlags(withStructPrefix(ctx, sf), field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.CanSet() || isStructSlice(field.Type()) {
+			continue
+		}
+
+		// Nil pointers use the zero value of their type as the default
+		if field.Kind() == reflect.Ptr {
+			if _, ok := getDecoder(field.Type()); !ok {
+				if field.IsNil() {
+					field = reflect.New(field.Type().Elem()).Elem()
+				} else {
+					field = field.Elem()
+				}
+			}
+		}
+
+		if field.Kind() == reflect.Struct && isNestedStruct(field.Type()) {
+			if err := registerStructFlags(withStructPrefix(ctx, sf), field); err != nil {
+				return err
+			}
+			continue
+		}
+
+		name, ok := fieldName(ctx, flagTagName, sf)
+		if !ok {
+			continue
+		}
+
+		if err := registerFlag(ctx, name, field, sf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// registerFlag creates the flag for the field
+func registerFlag(ctx context.Context, name string, field reflect.Value, sf reflect.StructField) error {
+	cmd := c.GetCmdFromContext(ctx)
+
+	short := sf.Tag.Get(shortTagName)
+	if len(short) > 1 {
+		return ErrInvalidTag{field: sf.Name, tag: shortTagName, value: short}
+	}
+	hidden, err := boolTag(sf, hiddenTagName)
+	if err != nil {
+		return err
+	}
+	persistent, err := boolTag(sf, persistentTagName)
+	if err != nil {
+		return err
+	}
+
+	// Persistent flags of parent commands are inherited so would be shadowed by a flag of the same name
+	for _, flags := range []*pflag.FlagSet{cmd.Flags(), cmd.PersistentFlags(), cmd.InheritedFlags()} {
+		if flags.Lookup(name) != nil {
+			return ErrDuplicateFlag{name}
+		}
+		if short != "" && flags.ShorthandLookup(short) != nil {
+			return ErrDuplicateFlag{short}
+		}
+	}
+
+	flags := cmd.Flags()
+	if persistent {
+		flags = cmd.PersistentFlags()
+	}
+	if !addFlag(flags, name, short, sf.Tag.Get(usageTagName), field, sf) {
+		return nil
+	}
+
+	if hidden {
+		return flags.MarkHidden(name)
+	}
+	return nil
+}
+
+// addFlag adds a flag of the type matching the field and returns false if the type has no matching flag
+func addFlag(flags *pflag.FlagSet, name string, short string, usage string, field reflect.Value, sf reflect.StructField) bool {
+	t := field.Type()
+
+	if unit := sf.Tag.Get(units.TagName); unit != "" && isInteger(t.Kind()) {
+		flags.StringP(name, short, formatUnit(unit, field), usage)
+		return true
+	}
+
+	// Types that are flag values themselves such as ByteSize are copied so the struct isn't changed by parsing flags
+	if field.CanAddr() {
+		if _, ok := field.Addr().Interface().(pflag.Value); ok {
+			value := reflect.New(t)
+			value.Elem().Set(field)
+			flags.VarP(value.Interface().(pflag.Value), name, short, usage)
+			return true
+		}
+	}
+
+	switch t {
+	case durationType:
+		flags.DurationP(name, short, time.Duration(field.Int()), usage)
+		return true
+	case timeType:
+		value := ""
+		if tm := field.Interface().(time.Time); !tm.IsZero() {
+			layout := sf.Tag.Get(layoutTagName)
+			if layout == "" {
+				layout = time.RFC3339
+			}
+			value = tm.Format(layout)
+		}
+		flags.StringP(name, short, value, usage)
+		return true
+	case ipType:
+		flags.IPP(name, short, field.Interface().(net.IP), usage)
+		return true
+	case ipNetType:
+		flags.IPNetP(name, short, field.Interface().(net.IPNet), usage)
+		return true
+	}
+
+	if isDecodable(t) {
+		flags.StringP(name, short, formatValue(field), usage)
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		flags.StringP(name, short, field.String(), usage)
+	case reflect.Bool:
+		flags.BoolP(name, short, field.Bool(), usage)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		flags.Int64P(name, short, field.Int(), usage)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		flags.Uint64P(name, short, field.Uint(), usage)
+	case reflect.Float32, reflect.Float64:
+		flags.Float64P(name, short, field.Float(), usage)
+	case reflect.Slice:
+		return addSliceFlag(flags, name, short, usage, field)
+	case reflect.Map:
+		return addMapFlag(flags, name, short, usage, field)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// addSliceFlag adds a slice flag of the matching type, or a string slice flag for other supported element types
+func addSliceFlag(flags *pflag.FlagSet, name string, short string, usage string, field reflect.Value) bool {
+	if !isValueType(field.Type().Elem()) {
+		return false
+	}
+
+	switch value := convertDefault(field).(type) {
+	case []string:
+		flags.StringSliceP(name, short, value, usage)
+	case []int:
+		flags.IntSliceP(name, short, value, usage)
+	case []int32:
+		flags.Int32SliceP(name, short, value, usage)
+	case []int64:
+		flags.Int64SliceP(name, short, value, usage)
+	case []uint:
+		flags.UintSliceP(name, short, value, usage)
+	case []float32:
+		flags.Float32SliceP(name, short, value, usage)
+	case []float64:
+		flags.Float64SliceP(name, short, value, usage)
+	case []bool:
+		flags.BoolSliceP(name, short, value, usage)
+	case []time.Duration:
+		flags.DurationSliceP(name, short, value, usage)
+	case []net.IP:
+		flags.IPSliceP(name, short, value, usage)
+	default:
+		values := make([]string, field.Len())
+		for i := range values {
+			values[i] = formatValue(field.Index(i))
+		}
+		flags.StringSliceP(name, short, values, usage)
+	}
+
+	return true
+}
+
+// addMapFlag adds a map flag of the matching type, or a string to string flag for other supported key and value types
+func addMapFlag(flags *pflag.FlagSet, name string, short string, usage string, field reflect.Value) bool {
+	t := field.Type()
+	if !isValueType(t.Key()) || !isValueType(t.Elem()) {
+		return false
+	}
+
+	switch value := convertDefault(field).(type) {
+	case map[string]string:
+		flags.StringToStringP(name, short, value, usage)
+	case map[string]int:
+		flags.StringToIntP(name, short, value, usage)
+	case map[string]int64:
+		flags.StringToInt64P(name, short, value, usage)
+	default:
+		values := make(map[string]string, field.Len())
+		iter := field.MapRange()
+		for iter.Next() {
+			values[formatValue(iter.Key())] = formatValue(iter.Value())
+		}
+		flags.StringToStringP(name, short, values, usage)
+	}
+
+	return true
+}
+
+// convertDefault converts a named slice or map type to its unnamed type so it can be passed to pflag
+func convertDefault(field reflect.Value) interface{} {
+	t := field.Type()
+	var unnamed reflect.Type
+	switch t.Kind() {
+	case reflect.Slice:
+		unnamed = reflect.SliceOf(t.Elem())
+	case reflect.Map:
+		unnamed = reflect.MapOf(t.Key(), t.Elem())
+	default:
+		return field.Interface()
+	}
+
+	return field.Convert(unnamed).Interface()
+}
+
+// formatValue formats a value as the string it would be parsed from
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return ""
+	}
+
+	// Methods such as url.URL's String have pointer receivers
+	i := v.Interface()
+	if v.CanAddr() {
+		i = v.Addr().Interface()
+	}
+
+	switch value := i.(type) {
+	case encoding.TextMarshaler:
+		b, err := value.MarshalText()
+		if err == nil {
+			return string(b)
+		}
+	case fmt.Stringer:
+		return value.String()
+	}
+
+	if v.IsZero() {
+		return ""
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// formatUnit formats an integer field using its unit so the default is shown as 512MiB rather than 536870912
+func formatUnit(unit string, field reflect.Value) string {
+	var n int64
+	if field.CanInt() {
+		n = field.Int()
+	} else {
+		n = int64(field.Uint())
+	}
+
+	switch unit {
+	case units.Bytes:
+		return units.ByteSize(n).String()
+	case units.Quantities:
+		return units.Quantity(n).String()
+	}
+
+	return strconv.FormatInt(n, 10)
+}
+
+// isInteger returns if the kind is a signed or unsigned integer
+func isInteger(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+
+	return false
+}
+
+// boolTag returns the value of a boolean tag such as hidden:"true"
+func boolTag(sf reflect.StructField, tag string) (bool, error) {
+	value := sf.Tag.Get(tag)
+	if value == "" {
+		return false, nil
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, ErrInvalidTag{field: sf.Name, tag: tag, value: value}
+	}
+
+	return b, nil
+}
@@ -0,0 +1,217 @@
+package parser
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+	"github.com/skos-ninja/config-loader/pkg/units"
+
+	"github.com/spf13/cobra"
+)
+
+func TestRegisterFlags(t *testing.T) {
+	type Database struct {
+		Host string `flag:"host" usage:"Database host"`
+		Port uint16 `flag:"port"`
+	}
+	type Test struct {
+		Name     string            `flag:"name" short:"n" usage:"Service name"`
+		Debug    bool              `flag:"debug" hidden:"true"`
+		Workers  int               `flag:"workers" persistent:"true"`
+		Ratio    float32           `flag:"ratio"`
+		Timeout  time.Duration     `flag:"timeout"`
+		Tags     []string          `flag:"tags"`
+		Ports    []int             `flag:"ports"`
+		Labels   map[string]string `flag:"labels"`
+		Limits   map[string]int    `flag:"limits"`
+		IP       net.IP            `flag:"ip"`
+		URL      *url.URL          `flag:"url"`
+		Cache    int64             `flag:"cache" unit:"bytes"`
+		Size     units.ByteSize    `flag:"size"`
+		Level    testLevel         `flag:"level"`
+		Optional *int              `flag:"optional"`
+		Database Database          `prefix:"DB_"`
+		Untagged string
+		Ignored  string `flag:"-"`
+	}
+
+	u, _ := url.Parse("https://example.com")
+	cfg := &Test{
+		Name:     "service",
+		Workers:  4,
+		Ratio:    0.5,
+		Timeout:  time.Second,
+		Tags:     []string{"a", "b"},
+		Ports:    []int{80},
+		Labels:   map[string]string{"team": "core"},
+		IP:       net.ParseIP("127.0.0.1"),
+		URL:      u,
+		Cache:    512 << 20,
+		Size:     1 << 30,
+		Database: Database{Host: "localhost", Port: 5432},
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	ctx := c.GetContextWithCmd(cmd)
+	if err := RegisterFlags(ctx, cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	wantFlags := []struct {
+		name     string
+		typ      string
+		defValue string
+	}{
+		{name: "name", typ: "string", defValue: "service"},
+		{name: "debug", typ: "bool", defValue: "false"},
+		{name: "workers", typ: "int64", defValue: "4"},
+		{name: "ratio", typ: "float64", defValue: "0.5"},
+		{name: "timeout", typ: "duration", defValue: "1s"},
+		{name: "tags", typ: "stringSlice", defValue: "[a,b]"},
+		{name: "ports", typ: "intSlice", defValue: "[80]"},
+		{name: "labels", typ: "stringToString", defValue: "[team=core]"},
+		{name: "limits", typ: "stringToInt", defValue: "[]"},
+		{name: "ip", typ: "ip", defValue: "127.0.0.1"},
+		{name: "url", typ: "string", defValue: "https://example.com"},
+		{name: "cache", typ: "string", defValue: "512MiB"},
+		{name: "size", typ: "byteSize", defValue: "1GiB"},
+		{name: "level", typ: "string", defValue: ""},
+		{name: "optional", typ: "int64", defValue: "0"},
+		{name: "db-host", typ: "string", defValue: "localhost"},
+		{name: "db-port", typ: "uint64", defValue: "5432"},
+	}
+	for _, want := range wantFlags {
+		// Persistent flags are only merged into the command's flags when they are parsed
+		f := cmd.Flags().Lookup(want.name)
+		if f == nil {
+			f = cmd.PersistentFlags().Lookup(want.name)
+		}
+		if f == nil {
+			t.Errorf("RegisterFlags() flag %s not registered", want.name)
+			continue
+		}
+		if f.Value.Type() != want.typ || f.DefValue != want.defValue {
+			t.Errorf("RegisterFlags() flag %s = %s %q, want %s %q", want.name, f.Value.Type(), f.DefValue, want.typ, want.defValue)
+		}
+	}
+
+	if f := cmd.Flags().Lookup("name"); f.Shorthand != "n" || f.Usage != "Service name" {
+		t.Errorf("RegisterFlags() name flag shorthand = %q usage = %q", f.Shorthand, f.Usage)
+	}
+	if !cmd.Flags().Lookup("debug").Hidden {
+		t.Error("RegisterFlags() debug flag not hidden")
+	}
+	if cmd.PersistentFlags().Lookup("workers") == nil {
+		t.Error("RegisterFlags() workers flag not persistent")
+	}
+	if cmd.Flags().Lookup("untagged") != nil || cmd.Flags().Lookup("ignored") != nil {
+		t.Error("RegisterFlags() registered flags for fields without flag tags")
+	}
+
+	// Registered flags must be read back by the flag parser
+	err := cmd.ParseFlags([]string{
+		"-n", "flag", "--workers", "8", "--ports", "1,2", "--limits", "cpu=2",
+		"--url", "https://flag.example.com", "--cache", "1GiB", "--size", "2GiB", "--level", "info", "--optional", "3",
+		"--db-port", "5433",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ParseStruct(ctx, cfg, false); err != nil {
+		t.Fatal(err)
+	}
+
+	if cfg.Name != "flag" || cfg.Workers != 8 || !reflect.DeepEqual(cfg.Ports, []int{1, 2}) ||
+		!reflect.DeepEqual(cfg.Limits, map[string]int{"cpu": 2}) || cfg.URL.Host != "flag.example.com" ||
+		cfg.Cache != 1<<30 || cfg.Size != 2<<30 || cfg.Level != 1 || cfg.Optional == nil || *cfg.Optional != 3 ||
+		cfg.Database.Port != 5433 || cfg.Database.Host != "localhost" {
+		t.Errorf("ParseStruct() = %+v", cfg)
+	}
+}
+
+func TestRegisterFlagsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		setup  func(cmd *cobra.Command)
+		parent func(cmd *cobra.Command)
+		s      interface{}
+		want   error
+	}{
+		{
+			name: "Duplicate field names",
+			s: &struct {
+				A, B string `flag:"same"`
+			}{},
+			want: ErrDuplicateFlag{"same"},
+		},
+		{
+			name:  "Already registered",
+			setup: func(cmd *cobra.Command) { cmd.PersistentFlags().String("name", "", "") },
+			s: &struct {
+				Name string `flag:"name"`
+			}{},
+			want: ErrDuplicateFlag{"name"},
+		},
+		{
+			name:   "Inherited from parent",
+			parent: func(cmd *cobra.Command) { cmd.PersistentFlags().String("config", "", "") },
+			s: &struct {
+				Config string `flag:"config"`
+			}{},
+			want: ErrDuplicateFlag{"config"},
+		},
+		{
+			name:   "Inherited shorthand",
+			parent: func(cmd *cobra.Command) { cmd.PersistentFlags().StringP("verbose", "v", "", "") },
+			s: &struct {
+				Version string `flag:"version" short:"v"`
+			}{},
+			want: ErrDuplicateFlag{"v"},
+		},
+		{
+			name: "Duplicate shorthand",
+			s: &struct {
+				A string `flag:"a" short:"x"`
+				B string `flag:"b" short:"x"`
+			}{},
+			want: ErrDuplicateFlag{"x"},
+		},
+		{
+			name: "Invalid shorthand",
+			s: &struct {
+				A string `flag:"a" short:"xy"`
+			}{},
+			want: ErrInvalidTag{field: "A", tag: shortTagName, value: "xy"},
+		},
+		{
+			name: "Invalid hidden",
+			s: &struct {
+				A string `flag:"a" hidden:"yes please"`
+			}{},
+			want: ErrInvalidTag{field: "A", tag: hiddenTagName, value: "yes please"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			if tt.setup != nil {
+				tt.setup(cmd)
+			}
+			if tt.parent != nil {
+				root := &cobra.Command{Use: "root"}
+				tt.parent(root)
+				root.AddCommand(cmd)
+			}
+
+			err := RegisterFlags(c.GetContextWithCmd(cmd), tt.s)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("RegisterFlags() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"reflect"
+	"strings"
+)
+
+type ErrInvalidSliceElement struct {
+	field string
+	index int
+	value string
+	err   error
+}
+
+func (e ErrInvalidSliceElement) Error() string {
+	return fmt.Sprintf("Invalid value %s at index %d of field %s: %v", e.value, e.index, e.field, e.err)
+}
+
+func (e ErrInvalidSliceElement) Unwrap() error {
+	return e.err
+}
+
+// setSlice sets a slice field from the field parser's string slice converting each element to the slice's type
+func setSlice(ctx context.Context, f FieldParser, tag string, field reflect.Value, sf reflect.StructField) error {
+	t := field.Type()
+	if !isValueType(t.Elem()) {
+		log.Printf("WARNING: Unsupported slice type found in struct: %s\n", t.Elem())
+		return errUnsupportedType
+	}
+
+	s, err := f.GetStringSlice(ctx, tag)
+	if err != nil {
+		return err
+	}
+
+	// Splitting an empty value gives a single empty element rather than an empty slice
+	if len(s) == 1 && s[0] == "" {
+		s = nil
+	}
+
+	result := reflect.MakeSlice(t, len(s), len(s))
+	for i, v := range s {
+		// Only strings keep their whitespace so "1, 2" can be parsed as numbers
+		if t.Elem().Kind() != reflect.String {
+			v = strings.TrimSpace(v)
+		}
+
+		value, err := parseValue(v, t.Elem())
+		if err != nil {
+			return ErrInvalidSliceElement{field: sf.Name, index: i, value: v, err: err}
+		}
+		result.Index(i).Set(value)
+	}
+
+	field.Set(result)
+	return nil
+}
@@ -0,0 +1,132 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSliceParse(t *testing.T) {
+	type Test struct {
+		Ints          []int           `env:"TEST_SLICE_INTS"`
+		Int8s         []int8          `env:"TEST_SLICE_INT8S"`
+		Uints         []uint32        `env:"TEST_SLICE_UINTS"`
+		Floats        []float64       `env:"TEST_SLICE_FLOATS"`
+		Bools         []bool          `env:"TEST_SLICE_BOOLS"`
+		Durations     []time.Duration `env:"TEST_SLICE_DURATIONS"`
+		IPs           []net.IP        `env:"TEST_SLICE_IPS"`
+		Empty         []int           `env:"TEST_SLICE_EMPTY"`
+		FlagInts      []int           `flag:"test-slice-ints"`
+		FlagFloats    []float64       `flag:"test-slice-floats"`
+		FlagBools     []bool          `flag:"test-slice-bools"`
+		FlagDurations []time.Duration `flag:"test-slice-durations"`
+	}
+
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().IntSlice("test-slice-ints", nil, "")
+	cmd.Flags().Float64Slice("test-slice-floats", nil, "")
+	cmd.Flags().BoolSlice("test-slice-bools", nil, "")
+	cmd.Flags().DurationSlice("test-slice-durations", nil, "")
+	err := cmd.Flags().Parse([]string{
+		"--test-slice-ints", "1,2", "--test-slice-ints", "3",
+		"--test-slice-floats", "0.5",
+		"--test-slice-bools", "true,false",
+		"--test-slice-durations", "1s,1m",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := c.GetContextWithCmd(cmd)
+
+	setEnv(t, env{name: "TEST_SLICE_INTS", value: "1, 2, -3"})
+	setEnv(t, env{name: "TEST_SLICE_INT8S", value: "127,-128"})
+	setEnv(t, env{name: "TEST_SLICE_UINTS", value: "4294967295"})
+	setEnv(t, env{name: "TEST_SLICE_FLOATS", value: "1.5,2"})
+	setEnv(t, env{name: "TEST_SLICE_BOOLS", value: "true,0"})
+	setEnv(t, env{name: "TEST_SLICE_DURATIONS", value: "30s,1h"})
+	setEnv(t, env{name: "TEST_SLICE_IPS", value: "127.0.0.1,::1"})
+	setEnv(t, env{name: "TEST_SLICE_EMPTY", value: "", forceEmpty: true})
+
+	got := &Test{}
+	err = ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{
+		Ints:          []int{1, 2, -3},
+		Int8s:         []int8{127, -128},
+		Uints:         []uint32{4294967295},
+		Floats:        []float64{1.5, 2},
+		Bools:         []bool{true, false},
+		Durations:     []time.Duration{30 * time.Second, time.Hour},
+		IPs:           []net.IP{net.ParseIP("127.0.0.1"), net.ParseIP("::1")},
+		Empty:         []int{},
+		FlagInts:      []int{1, 2, 3},
+		FlagFloats:    []float64{0.5},
+		FlagBools:     []bool{true, false},
+		FlagDurations: []time.Duration{time.Second, time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStruct() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSliceParseErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		value     string
+		s         interface{}
+		wantIndex int
+	}{
+		{
+			name:  "Invalid int",
+			value: "1,2,three",
+			s: &struct {
+				V []int `env:"TEST_SLICE_ERROR"`
+			}{},
+			wantIndex: 2,
+		},
+		{
+			name:  "Out of range",
+			value: "1,256",
+			s: &struct {
+				V []uint8 `env:"TEST_SLICE_ERROR"`
+			}{},
+			wantIndex: 1,
+		},
+		{
+			name:  "Invalid duration",
+			value: "1s,soon",
+			s: &struct {
+				V []time.Duration `env:"TEST_SLICE_ERROR"`
+			}{},
+			wantIndex: 1,
+		},
+		{
+			name:  "Invalid text",
+			value: "localhost",
+			s: &struct {
+				V []net.IP `env:"TEST_SLICE_ERROR"`
+			}{},
+			wantIndex: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, env{name: "TEST_SLICE_ERROR", value: tt.value})
+			err := ParseStruct(context.Background(), tt.s, false)
+			got := ErrInvalidSliceElement{}
+			if !errors.As(err, &got) || got.index != tt.wantIndex || got.field != "V" {
+				t.Errorf("ParseStruct() error = %v, want ErrInvalidSliceElement at index %d", err, tt.wantIndex)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type ErrEnvIndexGap struct {
+	prefix string
+	index  int
+}
+
+func (e ErrEnvIndexGap) Error() string {
+	return fmt.Sprintf("Environment variables missing for %s%d_ while later indexes are set", e.prefix, e.index)
+}
+
+// isStructSlice returns if the type is a slice of structs or struct pointers whose fields should be parsed
+func isStructSlice(t reflect.Type) bool {
+	if t.Kind() != reflect.Slice {
+		return false
+	}
+
+	elem := t.Elem()
+	if elem.Kind() == reflect.Ptr && !isDecodable(elem) {
+		elem = elem.Elem()
+	}
+	return isNestedStruct(elem)
+}
+
+// parseStructSlice sets a slice of structs from indexed environment variables.
+// A field tagged env:"SERVERS" has its elements' env tags prefixed with SERVERS_0_, SERVERS_1_ and so on.
+// Existing elements, including those past the last index, are kept with the variables for their index applied over them.
+func parseStructSlice(ctx context.Context, field reflect.Value, sf reflect.StructField, failOnParseError bool) (bool, error) {
+	name, ok := fieldName(ctx, envTagName, sf)
+	if !ok || !hasPrecedence(ctx, envTagName) {
+		return false, nil
+	}
+
+	n, err := envIndexCount(ctx, name+"_")
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	// Elements are only parsed from env as any other tag would be the same for every element
+	ctx = WithPrecedence(ctx, []string{envTagName})
+
+	t := field.Type()
+	size := n
+	if field.Len() > size {
+		size = field.Len()
+	}
+	result := reflect.MakeSlice(t, size, size)
+	reflect.Copy(result, field)
+	for i := 0; i < n; i++ {
+		elem := result.Index(i)
+
+		elemCtx := setTagPrefix(ctx, envTagName, name+"_"+strconv.Itoa(i)+"_")
+		if elem.Kind() == reflect.Ptr {
+			if elem.IsNil() {
+				elem.Set(reflect.New(t.Elem().Elem()))
+			}
+			elem = elem.Elem()
+		}
+
+		if _, err := parseStruct(elemCtx, elem, failOnParseError); err != nil {
+			return false, err
+		}
+	}
+
+	field.Set(result)
+	return true, nil
+}
+
+// envIndexCount returns the number of indexes with variables starting with the prefix such as SERVERS_0_HOST.
+// Indexes must start at 0 without any gaps.
+func envIndexCount(ctx context.Context, prefix string) (int, error) {
+	found := map[int]bool{}
+	for _, env := range GetEnviron(ctx)() {
+		name := strings.SplitN(env, "=", 2)[0]
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+
+		parts := strings.SplitN(name[len(prefix):], "_", 2)
+		if len(parts) != 2 || parts[1] == "" {
+			continue
+		}
+
+		// Only use canonical indexes so SERVERS_01_HOST isn't treated as index 1
+		i, err := strconv.Atoi(parts[0])
+		if err != nil || i < 0 || strconv.Itoa(i) != parts[0] {
+			continue
+		}
+		found[i] = true
+	}
+
+	indexes := make([]int, 0, len(found))
+	for i := range found {
+		indexes = append(indexes, i)
+	}
+	sort.Ints(indexes)
+
+	for i, index := range indexes {
+		if index != i {
+			return 0, ErrEnvIndexGap{prefix: prefix, index: i}
+		}
+	}
+
+	return len(indexes), nil
+}
@@ -0,0 +1,120 @@
+package parser
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// withTestEnv returns a context where only the variables are set
+func withTestEnv(ctx context.Context, vars map[string]string) context.Context {
+	ctx = WithEnvLookup(ctx, func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	})
+	return WithEnviron(ctx, func() []string {
+		var env []string
+		for k, v := range vars {
+			env = append(env, k+"="+v)
+		}
+		return env
+	})
+}
+
+func TestStructSliceParse(t *testing.T) {
+	type Route struct {
+		Path string `env:"PATH"`
+	}
+	type Server struct {
+		Host   string  `env:"HOST"`
+		Port   int     `env:"PORT"`
+		Routes []Route `env:"ROUTES"`
+	}
+	type Test struct {
+		Servers  []Server  `env:"SERVERS"`
+		Pointers []*Server `env:"POINTERS"`
+		Existing []Server  `env:"EXISTING"`
+		Unset    []Server  `env:"UNSET"`
+	}
+
+	ctx := withTestEnv(context.Background(), map[string]string{
+		"SERVERS_0_HOST":          "a",
+		"SERVERS_0_PORT":          "80",
+		"SERVERS_0_ROUTES_0_PATH": "/",
+		"SERVERS_0_ROUTES_1_PATH": "/api",
+		"SERVERS_1_HOST":          "b",
+		"SERVERS_01_HOST":         "ignored",
+		"POINTERS_0_HOST":         "c",
+		"EXISTING_0_PORT":         "8080",
+	})
+
+	got := &Test{
+		Existing: []Server{{Host: "default", Port: 1}, {Host: "kept", Port: 2}, {Host: "last"}},
+		Unset:    []Server{{Host: "default"}},
+	}
+	err := ParseStruct(ctx, got, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &Test{
+		Servers: []Server{
+			{Host: "a", Port: 80, Routes: []Route{{Path: "/"}, {Path: "/api"}}},
+			{Host: "b"},
+		},
+		Pointers: []*Server{{Host: "c"}},
+		Existing: []Server{{Host: "default", Port: 8080}, {Host: "kept", Port: 2}, {Host: "last"}},
+		Unset:    []Server{{Host: "default"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseStruct() = %+v, want %+v", got, want)
+	}
+}
+
+func TestStructSliceParseErrors(t *testing.T) {
+	type Server struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+	}
+	type Test struct {
+		Servers []Server `env:"SERVERS"`
+	}
+
+	t.Run("Gap", func(t *testing.T) {
+		ctx := withTestEnv(context.Background(), map[string]string{
+			"SERVERS_0_HOST": "a",
+			"SERVERS_2_HOST": "c",
+		})
+		err := ParseStruct(ctx, &Test{}, false)
+		want := ErrEnvIndexGap{prefix: "SERVERS_", index: 1}
+		if err != want {
+			t.Errorf("ParseStruct() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("Not starting at zero", func(t *testing.T) {
+		ctx := withTestEnv(context.Background(), map[string]string{"SERVERS_1_HOST": "b"})
+		err := ParseStruct(ctx, &Test{}, false)
+		want := ErrEnvIndexGap{prefix: "SERVERS_", index: 0}
+		if err != want {
+			t.Errorf("ParseStruct() error = %v, want %v", err, want)
+		}
+	})
+
+	t.Run("Invalid value", func(t *testing.T) {
+		ctx := withTestEnv(context.Background(), map[string]string{"SERVERS_0_PORT": "http"})
+		err := ParseStruct(ctx, &Test{}, false)
+		if err == nil {
+			t.Error("ParseStruct() expected error for invalid port")
+		}
+	})
+
+	t.Run("Missing field", func(t *testing.T) {
+		ctx := withTestEnv(context.Background(), map[string]string{"SERVERS_0_HOST": "a"})
+		err := ParseStruct(ctx, &Test{}, true)
+		if !errors.As(err, &ErrEnvVariableNotFound{}) {
+			t.Errorf("ParseStruct() error = %v, want ErrEnvVariableNotFound", err)
+		}
+	})
+}
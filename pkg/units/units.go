@@ -0,0 +1,266 @@
+package units
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// TagName is the tag used to parse integer fields as a unit such as unit:"bytes" or unit:"quantity"
+const TagName = "unit"
+
+const (
+	// Bytes is the unit for sizes such as 512KiB, 10MB or 1.5GiB
+	Bytes = "bytes"
+	// Quantities is the unit for counts such as 1k, 2.5M or 1_000_000
+	Quantities = "quantity"
+)
+
+type ErrUnknownUnit struct {
+	unit string
+}
+
+func (e ErrUnknownUnit) Error() string {
+	return fmt.Sprintf("Unknown unit: %s", e.unit)
+}
+
+type ErrInvalidSize struct {
+	value   string
+	message string
+}
+
+func (e ErrInvalidSize) Error() string {
+	return fmt.Sprintf("Invalid size %q: %s", e.value, e.message)
+}
+
+type suffix struct {
+	name       string
+	multiplier int64
+}
+
+// byteSuffixes are ordered largest first so sizes are formatted with the largest exact unit
+var byteSuffixes = []suffix{
+	{"PiB", 1 << 50}, {"PB", 1e15}, {"Pi", 1 << 50}, {"P", 1e15},
+	{"TiB", 1 << 40}, {"TB", 1e12}, {"Ti", 1 << 40}, {"T", 1e12},
+	{"GiB", 1 << 30}, {"GB", 1e9}, {"Gi", 1 << 30}, {"G", 1e9},
+	{"MiB", 1 << 20}, {"MB", 1e6}, {"Mi", 1 << 20}, {"M", 1e6},
+	{"KiB", 1 << 10}, {"KB", 1e3}, {"Ki", 1 << 10}, {"K", 1e3},
+	{"B", 1},
+}
+
+var quantitySuffixes = []suffix{
+	{"P", 1e15},
+	{"T", 1e12},
+	{"G", 1e9},
+	{"M", 1e6},
+	{"K", 1e3},
+}
+
+// Parse parses the value using the unit, either Bytes or Quantities
+func Parse(unit string, s string) (int64, error) {
+	switch unit {
+	case Bytes:
+		return ParseByteSize(s)
+	case Quantities:
+		return ParseQuantity(s)
+	}
+
+	return 0, ErrUnknownUnit{unit}
+}
+
+// ParseByteSize parses a size such as 512KiB, 10MB or 1.5GiB into a number of bytes.
+// KB, MB, GB, TB and PB are powers of 1000 while KiB, MiB, GiB, TiB and PiB are powers of 1024.
+// The B can be left off (10M, 1.5Gi), suffixes are case insensitive and a number without a suffix is bytes.
+func ParseByteSize(s string) (int64, error) {
+	n, err := parse(s, byteSuffixes)
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 {
+		return 0, ErrInvalidSize{s, "size can't be negative"}
+	}
+
+	return n, nil
+}
+
+// ParseQuantity parses a count such as 1k, 2.5M or 1_000_000.
+// The k, M, G, T and P suffixes are powers of 1000 and are case insensitive.
+func ParseQuantity(s string) (int64, error) {
+	return parse(s, quantitySuffixes)
+}
+
+// parse parses a number with an optional suffix that must give a whole number once multiplied
+func parse(s string, suffixes []suffix) (int64, error) {
+	value := strings.TrimSpace(s)
+
+	// Split the number from the suffix
+	end := 0
+	for end < len(value) && strings.IndexByte("+-0123456789._", value[end]) >= 0 {
+		end++
+	}
+	number, unit := value[:end], strings.TrimSpace(value[end:])
+
+	multiplier := int64(1)
+	if unit != "" {
+		found := false
+		for _, suf := range suffixes {
+			if strings.EqualFold(unit, suf.name) {
+				multiplier, found = suf.multiplier, true
+				break
+			}
+		}
+		if !found {
+			return 0, ErrInvalidSize{s, fmt.Sprintf("unknown suffix %s", unit)}
+		}
+	}
+
+	number, err := removeUnderscores(number)
+	if err != nil {
+		return 0, ErrInvalidSize{s, err.Error()}
+	}
+
+	r, ok := new(big.Rat).SetString(number)
+	if !ok || number == "" || strings.ContainsAny(number, "/eE") {
+		return 0, ErrInvalidSize{s, "invalid number"}
+	}
+
+	r.Mul(r, new(big.Rat).SetInt64(multiplier))
+	if !r.IsInt() {
+		return 0, ErrInvalidSize{s, "not a whole number"}
+	}
+	if !r.Num().IsInt64() {
+		return 0, ErrInvalidSize{s, "out of range"}
+	}
+
+	return r.Num().Int64(), nil
+}
+
+// removeUnderscores removes the underscores used to separate digits such as 1_000_000
+func removeUnderscores(s string) (string, error) {
+	if !strings.Contains(s, "_") {
+		return s, nil
+	}
+
+	isDigit := func(i int) bool {
+		return i >= 0 && i < len(s) && s[i] >= '0' && s[i] <= '9'
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '_' && (!isDigit(i-1) || !isDigit(i+1)) {
+			return "", fmt.Errorf("underscores must separate digits")
+		}
+	}
+
+	return strings.ReplaceAll(s, "_", ""), nil
+}
+
+// format formats n with the largest suffix it is a multiple of
+func format(n int64, suffixes []suffix) string {
+	for _, suf := range suffixes {
+		if n != 0 && suf.multiplier > 1 && n%suf.multiplier == 0 {
+			return strconv.FormatInt(n/suf.multiplier, 10) + suf.name
+		}
+	}
+
+	return strconv.FormatInt(n, 10)
+}
+
+// unmarshalJSON decodes a json number or string using the parse function
+func unmarshalJSON(data []byte, parse func(string) (int64, error)) (int64, error) {
+	var v interface{}
+	d := json.NewDecoder(strings.NewReader(string(data)))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return 0, err
+	}
+
+	switch v := v.(type) {
+	case json.Number:
+		return parse(v.String())
+	case string:
+		return parse(v)
+	}
+
+	return 0, fmt.Errorf("invalid size %s", data)
+}
+
+// ByteSize is a number of bytes parsed from sizes such as 512KiB, 10MB or 1.5GiB.
+// It can be decoded from env, flag and file tags, from numbers or strings in config files and used as a pflag.Value.
+type ByteSize int64
+
+// String formats the size with the largest binary unit it is a multiple of such as 512KiB
+func (b ByteSize) String() string {
+	return format(int64(b), []suffix{
+		{"PiB", 1 << 50}, {"TiB", 1 << 40}, {"GiB", 1 << 30}, {"MiB", 1 << 20}, {"KiB", 1 << 10},
+	})
+}
+
+func (b *ByteSize) UnmarshalText(text []byte) error {
+	return b.Set(string(text))
+}
+
+func (b *ByteSize) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalJSON(data, ParseByteSize)
+	if err != nil {
+		return err
+	}
+
+	*b = ByteSize(n)
+	return nil
+}
+
+// Set parses the size so a ByteSize can be used as a pflag.Value
+func (b *ByteSize) Set(s string) error {
+	n, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+
+	*b = ByteSize(n)
+	return nil
+}
+
+// Type returns the type name shown in pflag usage
+func (b *ByteSize) Type() string {
+	return "byteSize"
+}
+
+// Quantity is a count parsed from values such as 1k, 2.5M or 1_000_000.
+// It can be decoded from env, flag and file tags, from numbers or strings in config files and used as a pflag.Value.
+type Quantity int64
+
+// String formats the quantity with the largest suffix it is a multiple of such as 3M
+func (q Quantity) String() string {
+	return format(int64(q), []suffix{{"P", 1e15}, {"T", 1e12}, {"G", 1e9}, {"M", 1e6}, {"k", 1e3}})
+}
+
+func (q *Quantity) UnmarshalText(text []byte) error {
+	return q.Set(string(text))
+}
+
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	n, err := unmarshalJSON(data, ParseQuantity)
+	if err != nil {
+		return err
+	}
+
+	*q = Quantity(n)
+	return nil
+}
+
+// Set parses the quantity so a Quantity can be used as a pflag.Value
+func (q *Quantity) Set(s string) error {
+	n, err := ParseQuantity(s)
+	if err != nil {
+		return err
+	}
+
+	*q = Quantity(n)
+	return nil
+}
+
+// Type returns the type name shown in pflag usage
+func (q *Quantity) Type() string {
+	return "quantity"
+}
@@ -0,0 +1,126 @@
+package units
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "Bytes", args: "512", want: 512},
+		{name: "Bytes suffix", args: "512B", want: 512},
+		{name: "Binary", args: "512KiB", want: 512 << 10},
+		{name: "Decimal", args: "10MB", want: 10e6},
+		{name: "Fraction", args: "1.5GiB", want: 3 << 29},
+		{name: "Short binary", args: "2Gi", want: 2 << 30},
+		{name: "Short decimal", args: "10M", want: 10e6},
+		{name: "Lower case", args: "1kib", want: 1024},
+		{name: "Space", args: " 1 TiB ", want: 1 << 40},
+		{name: "Underscores", args: "1_048_576", want: 1 << 20},
+		{name: "Unknown suffix", args: "10XB", wantErr: true},
+		{name: "Fractional bytes", args: "1.5B", wantErr: true},
+		{name: "Negative", args: "-1KiB", wantErr: true},
+		{name: "Empty", args: "", wantErr: true},
+		{name: "Suffix only", args: "MiB", wantErr: true},
+		{name: "Exponent", args: "1e3", wantErr: true},
+		{name: "Overflow", args: "9000PiB", wantErr: true},
+		{name: "Leading underscore", args: "_1", wantErr: true},
+		{name: "Double underscore", args: "1__0", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseByteSize(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuantity(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    string
+		want    int64
+		wantErr bool
+	}{
+		{name: "Number", args: "42", want: 42},
+		{name: "Thousands", args: "1k", want: 1000},
+		{name: "Upper case", args: "1K", want: 1000},
+		{name: "Fraction", args: "2.5M", want: 2500000},
+		{name: "Underscores", args: "1_000_000", want: 1000000},
+		{name: "Negative", args: "-3G", want: -3e9},
+		{name: "Binary suffix", args: "1Ki", wantErr: true},
+		{name: "Fractional", args: "1.0001k", wantErr: true},
+		{name: "Trailing underscore", args: "1_", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseQuantity(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseQuantity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseQuantity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnknownUnit(t *testing.T) {
+	_, err := Parse("seconds", "1")
+	if err != (ErrUnknownUnit{"seconds"}) {
+		t.Errorf("Parse() error = %v, want ErrUnknownUnit", err)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{name: "ByteSize binary", got: ByteSize(512 << 10).String(), want: "512KiB"},
+		{name: "ByteSize largest unit", got: ByteSize(3 << 29).String(), want: "1536MiB"},
+		{name: "ByteSize bytes", got: ByteSize(1000).String(), want: "1000"},
+		{name: "ByteSize zero", got: ByteSize(0).String(), want: "0"},
+		{name: "Quantity", got: Quantity(3e6).String(), want: "3M"},
+		{name: "Quantity number", got: Quantity(2500).String(), want: "2500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("String() = %v, want %v", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var v struct {
+		Cache   ByteSize `json:"cache"`
+		Buffer  ByteSize `json:"buffer"`
+		Workers Quantity `json:"workers"`
+	}
+
+	err := json.Unmarshal([]byte(`{"cache": "1.5GiB", "buffer": 4096, "workers": "2k"}`), &v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v.Cache != 3<<29 || v.Buffer != 4096 || v.Workers != 2000 {
+		t.Errorf("json.Unmarshal() = %+v", v)
+	}
+
+	err = json.Unmarshal([]byte(`{"cache": true}`), &v)
+	if err == nil {
+		t.Error("json.Unmarshal() expected error for boolean size")
+	}
+}
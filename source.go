@@ -0,0 +1,176 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	c "github.com/skos-ninja/config-loader/pkg/context"
+	"github.com/skos-ninja/config-loader/pkg/parser"
+)
+
+// Schemes that can prefix a config value to set where it is read from
+const (
+	fileScheme = "file:"
+	envScheme  = "env:"
+	flagScheme = "flag:"
+	stdinValue = "-"
+)
+
+type ErrConfigNotFound struct {
+	config string
+	err    error
+}
+
+func (e ErrConfigNotFound) Error() string {
+	return fmt.Sprintf("Config not found: %s: %v", e.config, e.err)
+}
+
+func (e ErrConfigNotFound) Unwrap() error {
+	return e.err
+}
+
+func loadConfig(ctx context.Context, config interface{}, o *options) error {
+	values := configFlag
+	if len(values) == 0 {
+		files, err := searchConfigFiles(appName(ctx), initOpts)
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			values = append(values, fileScheme+f)
+		}
+	}
+
+	// Each config is merged onto the previous ones
+	for _, value := range values {
+		err := loadConfigValue(ctx, value, config, o)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func loadConfigValue(ctx context.Context, value string, config interface{}, o *options) error {
+	if o.legacyLookup && !hasScheme(value) {
+		return loadLegacyConfigValue(ctx, value, config, o)
+	}
+
+	if path, ok := filePath(value); ok && isDir(path) {
+		return loadConfigDir(ctx, path, config, o)
+	}
+
+	name, data, err := readConfigValue(ctx, value)
+	if err != nil {
+		return ErrConfigNotFound{value, err}
+	}
+
+	return setConfigFile(ctx, name, data, config, o)
+}
+
+// setConfigFile decodes the config data along with any includes and reports the file name if it was read from one
+func setConfigFile(ctx context.Context, name string, data string, config interface{}, o *options) error {
+	var parents []string
+	if name != "" {
+		abs, err := filepath.Abs(name)
+		if err != nil {
+			return err
+		}
+		parents = []string{abs}
+	}
+
+	err := setConfigIncludes(ctx, name, data, config, o, parents, 0)
+	if err != nil {
+		return err
+	}
+
+	if name != "" && o.fileLoaded != nil {
+		o.fileLoaded(name)
+	}
+
+	return nil
+}
+
+// appName returns the name of the root command used when searching for config files
+func appName(ctx context.Context) string {
+	cmd := c.GetCmdFromContext(ctx)
+	if cmd == nil {
+		return ""
+	}
+
+	return cmd.Root().Name()
+}
+
+// filePath returns the path of a value that references a file
+func filePath(value string) (string, bool) {
+	if strings.HasPrefix(value, fileScheme) {
+		return strings.TrimPrefix(value, fileScheme), true
+	}
+
+	return value, !hasScheme(value)
+}
+
+func hasScheme(value string) bool {
+	return value == stdinValue ||
+		strings.HasPrefix(value, fileScheme) ||
+		strings.HasPrefix(value, envScheme) ||
+		strings.HasPrefix(value, flagScheme)
+}
+
+// readConfigValue reads the config data referenced by the value along with the file name used to detect its format.
+// Values without a scheme are treated as file paths.
+func readConfigValue(ctx context.Context, value string) (string, string, error) {
+	switch {
+	case value == stdinValue:
+		var r io.Reader = os.Stdin
+		if cmd := c.GetCmdFromContext(ctx); cmd != nil {
+			r = cmd.InOrStdin()
+		}
+		b, err := io.ReadAll(r)
+		return "", string(b), err
+	case strings.HasPrefix(value, envScheme):
+		d, err := parser.EnvironmentParser{}.GetString(ctx, strings.TrimPrefix(value, envScheme))
+		return "", d, err
+	case strings.HasPrefix(value, flagScheme):
+		d, err := parser.FlagParser{}.LookupString(ctx, strings.TrimPrefix(value, flagScheme))
+		return "", d, err
+	default:
+		path, _ := filePath(value)
+		b, err := os.ReadFile(path)
+		return path, string(b), err
+	}
+}
+
+// loadLegacyConfigValue tries the value as a file path, upper case env name and flag name ignoring any that fail
+func loadLegacyConfigValue(ctx context.Context, name string, config interface{}, o *options) error {
+	// Try to read the config from a directory or file
+	if isDir(name) {
+		return loadConfigDir(ctx, name, config, o)
+	}
+	s, _ := os.ReadFile(name)
+	err := setConfig(name, string(s), config)
+	if err != nil {
+		return err
+	}
+
+	// Try to read the config from an env
+	d, _ := parser.EnvironmentParser{}.GetString(ctx, strings.ToUpper(name))
+	err = setConfig("", d, config)
+	if err != nil {
+		return err
+	}
+
+	// Try to read the config from a flag
+	flag, _ := parser.FlagParser{}.GetString(ctx, name)
+	err = setConfig("", flag, config)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestLoadConfigSources(t *testing.T) {
+	type Test struct {
+		Str string `json:"str"`
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeFile(t, path, "str: file")
+	t.Setenv("TEST_CONFIG_SOURCE", `{"str": "env"}`)
+	t.Setenv("TEST_CONFIG_SOURCE_PATH", `{"str": "legacy-env"}`)
+
+	tests := []struct {
+		name    string
+		config  string
+		stdin   string
+		opts    []Option
+		want    string
+		wantErr bool
+	}{
+		{
+			name:   "File path",
+			config: path,
+			want:   "file",
+		},
+		{
+			name:   "File scheme",
+			config: "file:" + path,
+			want:   "file",
+		},
+		{
+			name:    "Missing file",
+			config:  filepath.Join(dir, "missing.json"),
+			wantErr: true,
+		},
+		{
+			name:   "Directory",
+			config: "file:" + dir,
+			want:   "file",
+		},
+		{
+			name:   "Env scheme",
+			config: "env:TEST_CONFIG_SOURCE",
+			want:   "env",
+		},
+		{
+			name:    "Missing env",
+			config:  "env:TEST_CONFIG_SOURCE_MISSING",
+			wantErr: true,
+		},
+		{
+			name:   "Flag scheme",
+			config: "flag:inline-config",
+			want:   "flag",
+		},
+		{
+			name:   "Flag default",
+			config: "flag:default-config",
+			want:   "default",
+		},
+		{
+			name:    "Undefined flag",
+			config:  "flag:undefined-config",
+			wantErr: true,
+		},
+		{
+			name:   "Stdin",
+			config: "-",
+			stdin:  "str = \"stdin\"",
+			want:   "stdin",
+		},
+		{
+			name:   "Legacy lookup ignores missing file",
+			config: "test_config_source_path",
+			opts:   []Option{WithLegacyLookup()},
+			want:   "legacy-env",
+		},
+		{
+			name:    "Legacy lookup still errors for schemes",
+			config:  "env:TEST_CONFIG_SOURCE_MISSING",
+			opts:    []Option{WithLegacyLookup()},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{Use: "test"}
+			cmd.SetIn(strings.NewReader(tt.stdin))
+			Init(cmd)
+			cmd.Flags().String("inline-config", "", "")
+			cmd.Flags().String("default-config", `{"str": "default"}`, "")
+			err := cmd.ParseFlags([]string{"--config", tt.config, "--inline-config", `{"str": "flag"}`})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			cfg := &Test{}
+			err = Load(cmd, cfg, tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.As(err, &ErrConfigNotFound{}) {
+				t.Errorf("Load() error = %v, want ErrConfigNotFound", err)
+			}
+			if cfg.Str != tt.want {
+				t.Errorf("Load() = %v, want %v", cfg.Str, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+type ErrTOMLSyntax struct {
+	line   int
+	column int
+	err    error
+}
+
+func (e ErrTOMLSyntax) Error() string {
+	return fmt.Sprintf("Invalid toml at line %d, column %d: %v", e.line, e.column, e.err)
+}
+
+func (e ErrTOMLSyntax) Unwrap() error {
+	return e.err
+}
+
+// decodeTOML decodes toml so it can be set using the same field mapping as json
+func decodeTOML(configStr string) (interface{}, error) {
+	if configStr == "" {
+		return nil, nil
+	}
+
+	m := map[string]interface{}{}
+	_, err := toml.Decode(configStr, &m)
+	if err != nil {
+		var perr toml.ParseError
+		if errors.As(err, &perr) {
+			msg := perr.Message
+			if msg == "" {
+				msg = perr.Error()
+			}
+			return nil, ErrTOMLSyntax{
+				line:   perr.Position.Line,
+				column: tomlColumn(configStr, perr.Position),
+				err:    errors.New(msg),
+			}
+		}
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// tomlColumn returns the 1 based column of the position's byte offset within its line
+func tomlColumn(configStr string, pos toml.Position) int {
+	if pos.Start > len(configStr) {
+		return 1
+	}
+
+	lineStart := strings.LastIndex(configStr[:pos.Start], "\n") + 1
+	return pos.Start - lineStart + 1
+}
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type tomlServer struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+}
+
+type tomlTest struct {
+	Str      string       `json:"str"`
+	Inter    int64        `json:"inter"`
+	Created  time.Time    `json:"created"`
+	Database tomlServer   `json:"database"`
+	Inline   tomlServer   `json:"inline"`
+	Servers  []tomlServer `json:"servers"`
+}
+
+func TestSetTOMLConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    tomlTest
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			data: "",
+			want: tomlTest{},
+		},
+		{
+			name: "Tables, inline tables and arrays of tables",
+			data: `str = "test"
+inter = 9007199254740993
+created = 2021-01-02T03:04:05Z
+inline = { host = "inline", port = 1 }
+
+[database]
+host = "db"
+port = 5432
+
+[[servers]]
+host = "a"
+port = 1
+
+[[servers]]
+host = "b"
+port = 2
+`,
+			want: tomlTest{
+				Str:      "test",
+				Inter:    9007199254740993,
+				Created:  time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+				Database: tomlServer{Host: "db", Port: 5432},
+				Inline:   tomlServer{Host: "inline", Port: 1},
+				Servers:  []tomlServer{{Host: "a", Port: 1}, {Host: "b", Port: 2}},
+			},
+		},
+		{
+			name:    "Invalid type",
+			data:    `inter = "not-an-int"`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tomlTest{}
+			err := setConfig("config.toml", tt.data, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Created.Equal(tt.want.Created) {
+				t.Errorf("setConfig() created = %v, want %v", got.Created, tt.want.Created)
+			}
+			got.Created = tt.want.Created
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetTOMLConfigSyntaxError(t *testing.T) {
+	got := tomlTest{}
+	err := setConfig("config.toml", "str = \"test\"\ninter = 1\nport = = 2\n", &got)
+
+	var serr ErrTOMLSyntax
+	if !errors.As(err, &serr) {
+		t.Fatalf("setConfig() error = %v, want ErrTOMLSyntax", err)
+	}
+	if serr.line != 3 || serr.column != 8 {
+		t.Errorf("setConfig() position = %d:%d, want 3:8", serr.line, serr.column)
+	}
+}
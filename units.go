@@ -0,0 +1,137 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/skos-ninja/config-loader/pkg/units"
+)
+
+// ByteSize is a number of bytes parsed from sizes such as 512KiB, 10MB or 1.5GiB
+type ByteSize = units.ByteSize
+
+// Quantity is a count parsed from values such as 1k, 2.5M or 1_000_000
+type Quantity = units.Quantity
+
+type ErrInvalidUnitValue struct {
+	field string
+	err   error
+}
+
+func (e ErrInvalidUnitValue) Error() string {
+	return fmt.Sprintf("Invalid value for field %s: %v", e.field, e.err)
+}
+
+func (e ErrInvalidUnitValue) Unwrap() error {
+	return e.err
+}
+
+// convertUnitFields replaces the string values of integer fields tagged with unit in the decoded config with
+// their parsed numbers so they can be decoded as json
+func convertUnitFields(v interface{}, t reflect.Type) error {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		if m, ok := v.(map[string]interface{}); ok {
+			return convertUnitStruct(m, t)
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for _, e := range m {
+				if err := convertUnitFields(e, t.Elem()); err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		switch s := v.(type) {
+		case []interface{}:
+			for _, e := range s {
+				if err := convertUnitFields(e, t.Elem()); err != nil {
+					return err
+				}
+			}
+		case []map[string]interface{}:
+			for _, e := range s {
+				if err := convertUnitFields(e, t.Elem()); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func convertUnitStruct(m map[string]interface{}, t reflect.Type) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		name, _, _ := strings.Cut(sf.Tag.Get("json"), ",")
+		if name == "-" || (!sf.IsExported() && !sf.Anonymous) {
+			continue
+		}
+
+		// Embedded structs without a name have their fields promoted
+		if sf.Anonymous && name == "" {
+			ft := sf.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := convertUnitStruct(m, ft); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if name == "" {
+			name = sf.Name
+		}
+		key, ok := jsonKey(m, name)
+		if !ok {
+			continue
+		}
+
+		unit := sf.Tag.Get(units.TagName)
+		if unit == "" {
+			if err := convertUnitFields(m[key], sf.Type); err != nil {
+				return err
+			}
+			continue
+		}
+
+		s, ok := m[key].(string)
+		if !ok {
+			continue
+		}
+		n, err := units.Parse(unit, s)
+		if err != nil {
+			return ErrInvalidUnitValue{field: sf.Name, err: err}
+		}
+		m[key] = json.Number(strconv.FormatInt(n, 10))
+	}
+
+	return nil
+}
+
+// jsonKey finds the key json would decode into the field name, preferring an exact match over a case insensitive one
+func jsonKey(m map[string]interface{}, name string) (string, bool) {
+	if _, ok := m[name]; ok {
+		return name, true
+	}
+
+	for k := range m {
+		if strings.EqualFold(k, name) {
+			return k, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUnitConfig(t *testing.T) {
+	type Limits struct {
+		Body int64 `json:"body" unit:"bytes"`
+	}
+	type Embedded struct {
+		Workers int `json:"workers" unit:"quantity"`
+	}
+	type Test struct {
+		Embedded
+		Cache   int64             `json:"cache" unit:"bytes"`
+		Buffer  uint32            `unit:"bytes"`
+		Size    ByteSize          `json:"size"`
+		Count   Quantity          `json:"count"`
+		Limits  *Limits           `json:"limits"`
+		Routes  []Limits          `json:"routes"`
+		Tenants map[string]Limits `json:"tenants"`
+		Raw     int64             `json:"raw" unit:"bytes"`
+	}
+
+	tests := []struct {
+		name string
+		file string
+		data string
+	}{
+		{
+			name: "JSON",
+			file: "config.json",
+			data: `{"workers": "2k", "cache": "512MiB", "BUFFER": "64KiB", "size": "1.5GiB", "count": "1_000", "limits": {"body": "1MB"}, "routes": [{"body": "2MB"}], "tenants": {"a": {"body": "3MB"}}, "raw": 1024}`,
+		},
+		{
+			name: "YAML",
+			file: "config.yaml",
+			data: "workers: 2k\ncache: 512MiB\nbuffer: 64KiB\nsize: 1.5GiB\ncount: 1_000\nlimits:\n  body: 1MB\nroutes:\n  - body: 2MB\ntenants:\n  a:\n    body: 3MB\nraw: 1024\n",
+		},
+		{
+			name: "TOML",
+			file: "config.toml",
+			data: "workers = \"2k\"\ncache = \"512MiB\"\nbuffer = \"64KiB\"\nsize = \"1.5GiB\"\ncount = \"1_000\"\nraw = 1024\n[limits]\nbody = \"1MB\"\n[[routes]]\nbody = \"2MB\"\n[tenants.a]\nbody = \"3MB\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &Test{}
+			err := setConfig(tt.file, tt.data, got)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			want := &Test{
+				Embedded: Embedded{Workers: 2000},
+				Cache:    512 << 20,
+				Buffer:   64 << 10,
+				Size:     3 << 29,
+				Count:    1000,
+				Limits:   &Limits{Body: 1e6},
+				Routes:   []Limits{{Body: 2e6}},
+				Tenants:  map[string]Limits{"a": {Body: 3e6}},
+				Raw:      1024,
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("setConfig() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestUnitConfigError(t *testing.T) {
+	type Test struct {
+		Cache int64 `json:"cache" unit:"bytes"`
+	}
+
+	err := setConfig("config.json", `{"cache": "lots"}`, &Test{})
+	if !errors.As(err, &ErrInvalidUnitValue{}) {
+		t.Errorf("setConfig() error = %v, want ErrInvalidUnitValue", err)
+	}
+}
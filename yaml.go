@@ -0,0 +1,82 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// decodeYAML decodes yaml so it can be set using the same field mapping as json.
+// When the data contains multiple documents they are merged in order with later documents taking precedence.
+func decodeYAML(configStr string) (interface{}, error) {
+	if configStr == "" {
+		return nil, nil
+	}
+
+	var merged interface{}
+	d := yaml.NewDecoder(strings.NewReader(configStr))
+	for {
+		var doc interface{}
+		err := d.Decode(&doc)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// Skip empty documents
+		if doc == nil {
+			continue
+		}
+
+		merged = mergeYAML(merged, normaliseYAML(doc))
+	}
+
+	return merged, nil
+}
+
+// normaliseYAML converts any maps with non string keys into maps that can be encoded as json
+func normaliseYAML(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = normaliseYAML(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = normaliseYAML(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = normaliseYAML(e)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// mergeYAML merges src onto dst with maps merged by key and any other value replaced
+func mergeYAML(dst, src interface{}) interface{} {
+	dm, ok := dst.(map[string]interface{})
+	if !ok {
+		return src
+	}
+	sm, ok := src.(map[string]interface{})
+	if !ok {
+		return src
+	}
+
+	for k, v := range sm {
+		dm[k] = mergeYAML(dm[k], v)
+	}
+
+	return dm
+}
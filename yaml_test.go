@@ -0,0 +1,97 @@
+package config
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type yamlNested struct {
+	Field string `json:"field"`
+}
+
+type yamlTest struct {
+	Str    string            `json:"str"`
+	Inter  int               `json:"inter"`
+	Slice  []string          `json:"slice"`
+	Map    map[string]string `json:"map"`
+	Nested yamlNested        `json:"nested"`
+}
+
+func TestSetYAMLConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    yamlTest
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			data: "",
+			want: yamlTest{},
+		},
+		{
+			name: "Single document",
+			data: "str: test\ninter: 100\nslice: [a, b]\nmap:\n  key: value\nnested:\n  field: nested\n",
+			want: yamlTest{
+				Str:    "test",
+				Inter:  100,
+				Slice:  []string{"a", "b"},
+				Map:    map[string]string{"key": "value"},
+				Nested: yamlNested{Field: "nested"},
+			},
+		},
+		{
+			name: "Multiple documents are merged",
+			data: "str: first\nnested:\n  field: first\nmap:\n  a: a\n---\n---\nstr: second\nmap:\n  b: b\n",
+			want: yamlTest{
+				Str:    "second",
+				Map:    map[string]string{"a": "a", "b": "b"},
+				Nested: yamlNested{Field: "first"},
+			},
+		},
+		{
+			name: "Non string keys",
+			data: "map:\n  1: one\n  true: yes\n",
+			want: yamlTest{
+				Map: map[string]string{"1": "one", "true": "yes"},
+			},
+		},
+		{
+			name:    "Invalid yaml",
+			data:    "str: [",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid type",
+			data:    "inter: not-an-int",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := yamlTest{}
+			err := setConfig("config.yaml", tt.data, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("setConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("setConfig() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetYAMLConfigUnsupportedValue(t *testing.T) {
+	err := setConfig("config.yaml", "nested:\n  ratios: [1.5, .inf]\n", &yamlTest{})
+	var uerr ErrUnsupportedConfigValue
+	if !errors.As(err, &uerr) {
+		t.Fatalf("setConfig() error = %v, want ErrUnsupportedConfigValue", err)
+	}
+	if uerr.key != "nested.ratios[1]" {
+		t.Errorf("setConfig() key = %s, want nested.ratios[1]", uerr.key)
+	}
+}